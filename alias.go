@@ -0,0 +1,183 @@
+package typed
+
+import (
+	"maps"
+	"slices"
+)
+
+// First returns the value of the first of paths present within doc, an M, D or A,
+// and that path. Paths holding null count as absent. Numbers convert to the integer
+// and floating-point types T as Slices does. It returns false if none of paths is
+// present, or if the value of the first present is of another type.
+func First[T any](doc any, paths ...string) (T, string, bool) {
+	for _, path := range paths {
+		if v, ok := present(doc, path); ok {
+			e, ok := element[T](v)
+			return e, path, ok
+		}
+	}
+	var zero T
+	return zero, "", false
+}
+
+// present returns the value for path within doc, unless it is missing or null.
+func present(doc any, path string) (any, bool) {
+	v, _, result := walk(doc, path)
+	return v, result == lookupFound && v != nil
+}
+
+// FirstString is the same as StringValueOK, except it tries each of paths in turn,
+// such as the current and former names of a field, and returns the first present and
+// the value for it.
+func (m M) FirstString(paths ...string) (string, string, bool) {
+	return First[string](m, paths...)
+}
+
+// FirstFloat is the same as FloatOK, except it tries each of paths in turn, such as the
+// current and former names of a field, and returns the first present and the value for
+// it.
+func (m M) FirstFloat(paths ...string) (float64, string, bool) {
+	return First[float64](m, paths...)
+}
+
+// FirstAsInt64 is the same as AsInt64OK, except it tries each of paths in turn, such as
+// the current and former names of a field, and returns the first present and the value
+// for it.
+func (m M) FirstAsInt64(paths ...string) (int64, string, bool) {
+	return First[int64](m, paths...)
+}
+
+// FirstBool is the same as BoolOK, except it tries each of paths in turn, such as the
+// current and former names of a field, and returns the first present and the value for
+// it.
+func (m M) FirstBool(paths ...string) (bool, string, bool) {
+	return First[bool](m, paths...)
+}
+
+// FirstDocument is the same as DocumentOK, except it tries each of paths in turn, such
+// as the current and former names of a field, and returns the first present and the
+// value for it.
+func (m M) FirstDocument(paths ...string) (M, string, bool) {
+	return First[M](m, paths...)
+}
+
+// FirstArray is the same as ArrayOK, except it tries each of paths in turn, such as the
+// current and former names of a field, and returns the first present and the value for
+// it.
+func (m M) FirstArray(paths ...string) (A, string, bool) {
+	return First[A](m, paths...)
+}
+
+// Aliases maps canonical paths to the paths formerly used for the same values, such
+// as "user.full_name" to "user.name" and "username".
+type Aliases map[string][]string
+
+// Apply returns a view of m in which the values of the canonical paths absent from m
+// are set to the values of their first present alias, so all accessors resolve the
+// aliases. Nulls count as absent, as they do for First. The documents and arrays of m
+// are shared, except those holding the values set, which are copied; m is not
+// modified. Values can't be set within arrays beyond their ends, nor at malformed paths.
+func (al Aliases) Apply(m M) M {
+	return al.apply(m).(M)
+}
+
+// ApplyD is the same as Apply, except it applies to a D.
+func (al Aliases) ApplyD(d D) D {
+	return al.apply(d).(D)
+}
+
+// ApplyA is the same as Apply, except it applies to an A, whose canonical paths start
+// with the index of an element.
+func (al Aliases) ApplyA(a A) A {
+	return al.apply(a).(A)
+}
+
+func (al Aliases) apply(doc any) any {
+	out := doc
+	for _, canonical := range slices.Sorted(maps.Keys(al)) {
+		if _, ok := present(out, canonical); ok {
+			continue
+		}
+		segs, err := segments(canonical)
+		if err != nil {
+			continue
+		}
+		for _, alias := range al[canonical] {
+			v, ok := present(doc, alias)
+			if !ok {
+				continue
+			}
+			if c, ok := setCopy(out, segs, v); ok {
+				out = c
+			}
+			break
+		}
+	}
+	return out
+}
+
+// setCopy returns a copy of a with the value for segs set to v, copying the documents
+// and arrays on the way only. Missing documents are created as M within an M and as D
+// within a D.
+func setCopy(a any, segs []segment, v any) (any, bool) {
+	seg := segs[0]
+	if seg.slice {
+		return nil, false
+	}
+
+	set := func(old any, found bool, empty any) (any, bool) {
+		if len(segs) == 1 {
+			return v, true
+		}
+		if !found || old == nil {
+			old = empty
+		}
+		return setCopy(old, segs[1:], v)
+	}
+
+	switch x := a.(type) {
+	case M:
+		old, found := x[seg.key]
+		c, ok := set(old, found, M{})
+		if !ok {
+			return nil, false
+		}
+		m := make(M, len(x)+1)
+		maps.Copy(m, x)
+		m[seg.key] = c
+		return m, true
+	case map[string]any:
+		return setCopy(M(x), segs, v)
+	case D:
+		i := x.index(seg.key)
+		var old any
+		if i >= 0 {
+			old = x[i].Value
+		}
+		c, ok := set(old, i >= 0, D{})
+		if !ok {
+			return nil, false
+		}
+		if i < 0 {
+			return append(slices.Clip(x), E{seg.key, c}), true
+		}
+		d := slices.Clone(x)
+		d[i].Value = c
+		return d, true
+	case A:
+		i, ok := seg.element(len(x))
+		if !ok {
+			return nil, false
+		}
+		c, ok := set(x[i], true, nil)
+		if !ok {
+			return nil, false
+		}
+		s := slices.Clone(x)
+		s[i] = c
+		return s, true
+	case []any:
+		return setCopy(A(x), segs, v)
+	}
+	return nil, false
+}
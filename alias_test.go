@@ -0,0 +1,135 @@
+package typed
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestFirst(t *testing.T) {
+	t.Parallel()
+
+	m := M{
+		"user":     M{"name": "Wednesday", "age": 6.0},
+		"username": "wed",
+		"tags":     A{"a"},
+	}
+
+	name, path, ok := m.FirstString("user.full_name", "user.name", "username")
+	equal(t, "Wednesday", name)
+	equal(t, "user.name", path)
+	equal(t, true, ok)
+
+	_, path, ok = m.FirstString("user.age", "username")
+	equal(t, "user.age", path)
+	equal(t, false, ok)
+
+	_, path, ok = m.FirstBool("active", "enabled")
+	equal(t, "", path)
+	equal(t, false, ok)
+
+	age, _, _ := m.FirstAsInt64("user.years", "user.age")
+	equal(t, int64(6), age)
+	f, _, _ := m.FirstFloat("user.age")
+	equal(t, 6.0, f)
+	doc, _, _ := m.FirstDocument("profile", "user")
+	equal(t, "Wednesday", doc.StringValue("name"))
+	arr, _, _ := m.FirstArray("labels", "tags")
+	equal(t, 1, len(arr))
+
+	n, path, ok := First[int](D{{"v1", "x"}, {"v2", 2.0}}, "v3", "v2")
+	equal(t, 2, n)
+	equal(t, "v2", path)
+	equal(t, true, ok)
+}
+
+func TestAliases(t *testing.T) {
+	t.Parallel()
+
+	var m M
+	err := json.Unmarshal([]byte(`{
+		"user": {"name": "Wednesday", "id": 7},
+		"username": "wed",
+		"items": [{"qty": 2}],
+		"scalar": 1
+	}`), &m)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	aliases := Aliases{
+		"user.full_name": {"user.fullName", "user.name", "username"},
+		"user.id":        {"uid"},
+		"login":          {"username"},
+		"items.0.count":  {"items.0.qty"},
+		"meta.source":    {"user.id"},
+		"scalar.x":       {"username"},
+		"missing":        {"absent"},
+		`bad["path`:      {"username"},
+	}
+	v := aliases.Apply(m)
+
+	equal(t, "Wednesday", v.StringValue("user.full_name"))
+	equal(t, 7, v.AsInt("user.id"))
+	equal(t, "wed", v.StringValue("login"))
+	equal(t, 2, v.AsInt("items.0.count"))
+	equal(t, 7, v.AsInt("meta.source"))
+	equal(t, false, v.Exists("missing"))
+	equal(t, 1, v.AsInt("scalar"))
+
+	equal(t, false, m.Exists("user.full_name"))
+	equal(t, false, m.Exists("login"))
+	equal(t, false, m.Exists("items.0.count"))
+	equal(t, false, m.Exists("meta"))
+
+	equal(t, 0, len(Aliases{"a.b": {"c"}}.Apply(nil)))
+
+	d := D{{"username", "wed"}, {"user", D{{"id", 7.0}}}}
+	vd := aliases.ApplyD(d)
+	equalSlice(t, []string{"username", "user", "login", "meta", "scalar"}, vd.Keys())
+	equal(t, "wed", vd.StringValue("user.full_name"))
+	equal(t, 1, len(d.Document("user")))
+	meta, ok := vd.DocumentOK("meta")
+	equal(t, true, ok)
+	equal(t, 7, meta.AsInt("source"))
+
+	a := A{M{"qty": 2.0}, M{"count": 1.0}}
+	va := Aliases{"0.count": {"0.qty"}, "1.count": {"1.qty"}}.ApplyA(a)
+	equal(t, 2, va.AsInt("0.count"))
+	equal(t, 1, va.AsInt("1.count"))
+	equal(t, false, a.Exists("0.count"))
+	equal(t, "x", Aliases{"a.b": {"c"}}.Apply(M{"c": "x"}).StringValue("a.b"))
+}
+
+func TestAliases_Null(t *testing.T) {
+	t.Parallel()
+
+	var m M
+	err := json.Unmarshal([]byte(`{
+		"user": {"full_name": null, "name": "Wednesday"},
+		"meta": null,
+		"username": null,
+		"login": "wed"
+	}`), &m)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	name, path, ok := m.FirstString("user.full_name", "user.name")
+	equal(t, "Wednesday", name)
+	equal(t, "user.name", path)
+	equal(t, true, ok)
+
+	_, _, ok = m.FirstString("username")
+	equal(t, false, ok)
+
+	v := Aliases{
+		"user.full_name": {"user.name"},
+		"meta.source":    {"username", "login"},
+		"nickname":       {"username"},
+	}.Apply(m)
+	equal(t, "Wednesday", v.StringValue("user.full_name"))
+	equal(t, "wed", v.StringValue("meta.source"))
+	equal(t, false, v.Exists("nickname"))
+	equal(t, nil, m.Document("user")["full_name"])
+	equal(t, nil, m["meta"])
+}
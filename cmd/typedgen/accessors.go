@@ -0,0 +1,211 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/weiwenchen2022/typed"
+)
+
+// reservedMethods returns the names the methods of a wrapper must not take: the
+// embedded field M and the methods promoted from *typed.M, which a method of the same
+// name would shadow.
+func reservedMethods() map[string]bool {
+	t := reflect.TypeFor[*typed.M]()
+	methods := map[string]bool{"M": true}
+	for i := range t.NumMethod() {
+		methods[t.Method(i).Name] = true
+	}
+	return methods
+}
+
+// generateAccessors returns the Go source of wrapper types for the model. Each
+// object type becomes a struct embedding typed.M, with a getter and a setter per
+// property implemented with the typed.M accessors, so fields the schema does not
+// describe survive round trips.
+//
+// Getters of required, non-nullable properties panic like the typed.M accessors
+// they call; getters of other properties return an additional boolean instead.
+func generateAccessors(pkg string, m *model) ([]byte, error) {
+	var body bytes.Buffer
+	usesTime := false
+
+	for _, s := range m.types {
+		recv := strings.ToLower(s.name[:1])
+		if strings.Contains("adeimsv", recv) {
+			// Avoid the names of the generated local variables.
+			recv = "x"
+		}
+
+		fmt.Fprintf(&body, "// %s wraps a document; fields it does not describe are preserved.\n", s.name)
+		fmt.Fprintf(&body, "type %s struct{ typed.M }\n\n", s.name)
+
+		methods := reservedMethods()
+		for _, f := range s.fields {
+			if containsKind(f.typ, kindTime) {
+				usesTime = true
+			}
+
+			name := f.name
+			for methods[name] || methods["Set"+name] {
+				name += "Field"
+			}
+			methods[name], methods["Set"+name] = true, true
+
+			a := accessorOf(f.typ, !f.required || f.typ.nullable)
+			// Paths escape the dots and brackets of keys; indexing the map does not.
+			path, key := strconv.Quote(typed.EscapeKey(f.key)), strconv.Quote(f.key)
+			fmt.Fprintf(&body, "func (%s %s) %s() %s {\n%s}\n\n",
+				recv, s.name, name, a.result, fmt.Sprintf(a.get, recv+".M", path, key))
+			fmt.Fprintf(&body, "func (%s %s) Set%s(v %s) {\n%s}\n\n",
+				recv, s.name, name, a.typ, fmt.Sprintf(a.set, recv+".M", path, key))
+		}
+	}
+
+	var src bytes.Buffer
+	fmt.Fprintf(&src, "// Code generated by typedgen; DO NOT EDIT.\n\npackage %s\n\n", pkg)
+	fmt.Fprintf(&src, "import (\n")
+	if usesTime {
+		fmt.Fprintf(&src, "\t\"time\"\n\n")
+	}
+	fmt.Fprintf(&src, "\t\"github.com/weiwenchen2022/typed\"\n)\n\n")
+	src.Write(body.Bytes())
+	return format.Source(src.Bytes())
+}
+
+// accessor holds the code of the getter and setter of a property. The get and set
+// formats take the document, path and key expressions as arguments.
+type accessor struct {
+	typ    string // Go type of the property
+	result string // result list of the getter
+	get    string
+	set    string
+}
+
+// accessorOf returns the accessor of a property of type t, whose getter reports
+// with a boolean whether the value is present if optional is true.
+func accessorOf(t *goType, optional bool) accessor {
+	var a accessor
+	switch t.kind {
+	case kindBool, kindFloat, kindString:
+		method := map[kind]string{kindBool: "Bool", kindFloat: "Float", kindString: "StringValue"}[t.kind]
+		a.typ = structFieldType(&goType{kind: t.kind})
+		a.get = "return %[1]s." + method + "(%[2]s)\n"
+		if optional {
+			a.get = "return %[1]s." + method + "OK(%[2]s)\n"
+		}
+		a.set = "%[1]s.Set(%[2]s, v)\n"
+	case kindInt:
+		a.typ = "int64"
+		a.get = "return %[1]s.AsInt64(%[2]s)\n"
+		if optional {
+			a.get = "return %[1]s.AsInt64OK(%[2]s)\n"
+		}
+		a.set = "%[1]s.Set(%[2]s, float64(v))\n"
+	case kindTime:
+		a.typ = "time.Time"
+		a.get = "return %[1]s.AsTime(%[2]s)\n"
+		if optional {
+			a.get = "return %[1]s.AsTimeOK(%[2]s)\n"
+		}
+		a.set = "%[1]s.Set(%[2]s, v.Format(time.RFC3339Nano))\n"
+	case kindStruct:
+		a.typ = t.strct.name
+		a.get = "return " + a.typ + "{%[1]s.Document(%[2]s)}\n"
+		if optional {
+			a.get = "doc, ok := %[1]s.DocumentOK(%[2]s)\nreturn " + a.typ + "{doc}, ok\n"
+		}
+		a.set = "%[1]s.Set(%[2]s, v.M)\n"
+	case kindMap:
+		a.typ = "typed.M"
+		a.get = "return %[1]s.Document(%[2]s)\n"
+		if optional {
+			a.get = "return %[1]s.DocumentOK(%[2]s)\n"
+		}
+		a.set = "%[1]s.Set(%[2]s, v)\n"
+	case kindSlice:
+		a = sliceAccessorOf(t.elem, optional)
+	default:
+		a.typ = "any"
+		a.get = "return %[1]s[%[3]s]\n"
+		if optional {
+			a.get = "v, ok := %[1]s[%[3]s]\nreturn v, ok\n"
+		}
+		a.set = "%[1]s.Set(%[2]s, v)\n"
+	}
+
+	a.result = a.typ
+	if optional {
+		a.result = "(" + a.typ + ", bool)"
+	}
+	return a
+}
+
+// sliceAccessorOf returns the accessor of a property holding an array with
+// elements of type elem.
+func sliceAccessorOf(elem *goType, optional bool) accessor {
+	var a accessor
+
+	array := "a := %[1]s.Array(%[2]s)\n"
+	if optional {
+		array = "a, ok := %[1]s.ArrayOK(%[2]s)\nif !ok {\nreturn nil, false\n}\n"
+	}
+	ret := func(expr string) string {
+		if optional {
+			return array + "return " + expr + "OK()\n"
+		}
+		return array + "return " + expr + "()\n"
+	}
+
+	k := elem.kind
+	if elem.nullable {
+		// Nulls are not converted by the typed.A conversions.
+		k = kindAny
+	}
+	switch k {
+	case kindBool:
+		a.typ, a.get = "[]bool", ret("a.Bools")
+	case kindInt:
+		a.typ, a.get = "[]int64", ret("a.AsInt64s")
+	case kindFloat:
+		a.typ, a.get = "[]float64", ret("a.Floats")
+	case kindString:
+		a.typ, a.get = "[]string", ret("a.Strings")
+	case kindStruct:
+		a.typ = "[]" + elem.strct.name
+		if optional {
+			a.get = array + "docs, ok := a.DocumentsOK()\nif !ok {\nreturn nil, false\n}\n"
+		} else {
+			a.get = array + "docs := a.Documents()\n"
+		}
+		a.get += "s := make(" + a.typ + ", len(docs))\nfor i, d := range docs {\ns[i] = " +
+			elem.strct.name + "{d}\n}\n"
+		if optional {
+			a.get += "return s, true\n"
+		} else {
+			a.get += "return s\n"
+		}
+	default:
+		a.typ = "typed.A"
+		a.get = "return %[1]s.Array(%[2]s)\n"
+		if optional {
+			a.get = "return %[1]s.ArrayOK(%[2]s)\n"
+		}
+		a.set = "%[1]s.Set(%[2]s, v)\n"
+		return a
+	}
+
+	elemExpr := "e"
+	switch k {
+	case kindInt:
+		elemExpr = "float64(e)"
+	case kindStruct:
+		elemExpr = "e.M"
+	}
+	a.set = "a := make(typed.A, len(v))\nfor i, e := range v {\na[i] = " + elemExpr + "\n}\n%[1]s.Set(%[2]s, a)\n"
+	return a
+}
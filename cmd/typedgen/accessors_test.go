@@ -0,0 +1,122 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/weiwenchen2022/typed"
+)
+
+func TestGenerateAccessors(t *testing.T) {
+	t.Parallel()
+
+	samples, err := readValues(strings.NewReader(`
+		{"id": 1, "status": "paid", "items": [{"sku": "A1", "qty": 2}], "note": null, "a.b": 1}
+		{"id": 2, "status": "new", "items": [], "note": "gift", "a.b": 2}
+	`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	src, err := generateAccessors("orders", newModel("Order", typed.InferSchema(samples...)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, s := range []string{
+		"type Order struct{ typed.M }\n",
+		"func (o Order) ID() int64 {\n\treturn o.M.AsInt64(\"id\")\n}\n",
+		"func (o Order) SetID(v int64) {\n\to.M.Set(\"id\", float64(v))\n}\n",
+		"func (o Order) SetStatus(v string) {\n\to.M.Set(\"status\", v)\n}\n",
+		"func (o Order) Items() []OrderItem {\n",
+		"func (o Order) Note() (string, bool) {\n\treturn o.M.StringValueOK(\"note\")\n}\n",
+		"type OrderItem struct{ typed.M }\n",
+		"func (o Order) AB() int64 {\n\treturn o.M.AsInt64(\"a\\\\.b\")\n}\n",
+	} {
+		if !strings.Contains(string(src), s) {
+			t.Errorf("missing %q in:\n%s", s, src)
+		}
+	}
+}
+
+func TestGenerateAccessors_Receiver(t *testing.T) {
+	t.Parallel()
+
+	schema := typed.M{
+		"type":       "object",
+		"properties": typed.M{"m": typed.M{"type": "string"}},
+		"required":   typed.A{"m"},
+	}
+	src, err := generateAccessors("main", newModel("Address", schema))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, s := range []string{"func (x Address) MField() string {\n", "func (x Address) SetMField(v string) {\n"} {
+		if !strings.Contains(string(src), s) {
+			t.Errorf("missing %q in:\n%s", s, src)
+		}
+	}
+}
+
+func TestGenerateAccessors_Reserved(t *testing.T) {
+	t.Parallel()
+
+	schema := typed.M{
+		"type": "object",
+		"properties": typed.M{
+			"keys":     typed.M{"type": "array", "items": typed.M{"type": "string"}},
+			"document": typed.M{"type": "string"},
+			"exists":   typed.M{"type": "boolean"},
+		},
+		"required": typed.A{"keys", "document", "exists"},
+	}
+	src, err := generateAccessors("main", newModel("Index", schema))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const want = `// Code generated by typedgen; DO NOT EDIT.
+
+package main
+
+import (
+	"github.com/weiwenchen2022/typed"
+)
+
+// Index wraps a document; fields it does not describe are preserved.
+type Index struct{ typed.M }
+
+func (x Index) DocumentField() string {
+	return x.M.StringValue("document")
+}
+
+func (x Index) SetDocumentField(v string) {
+	x.M.Set("document", v)
+}
+
+func (x Index) ExistsField() bool {
+	return x.M.Bool("exists")
+}
+
+func (x Index) SetExistsField(v bool) {
+	x.M.Set("exists", v)
+}
+
+func (x Index) KeysField() []string {
+	a := x.M.Array("keys")
+	return a.Strings()
+}
+
+func (x Index) SetKeysField(v []string) {
+	a := make(typed.A, len(v))
+	for i, e := range v {
+		a[i] = e
+	}
+	x.M.Set("keys", a)
+}
+`
+	if string(src) != want {
+		t.Errorf("got:\n%s\nwant:\n%s", src, want)
+	}
+}
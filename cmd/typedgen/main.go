@@ -0,0 +1,150 @@
+// Typedgen generates Go type definitions from sample JSON documents or a JSON Schema.
+//
+// Usage:
+//
+//	typedgen [flags] [file ...]
+//
+// Each file holds one or more JSON documents; with no files, standard input is read.
+// By default the files are samples whose schema is inferred with typed.InferSchema;
+// with -schema, the single input is a JSON Schema.
+//
+// The generated struct types carry json tags, use int64 for integral numbers,
+// float64 for other numbers, time.Time for RFC 3339 strings, pointers for nullable
+// values and a named type for every nested object.
+//
+// With -accessors, every object type is instead generated as a wrapper embedding
+// typed.M, with getter and setter methods per property implemented with the typed.M
+// accessors. The document stays intact, so fields the schema does not describe
+// survive round trips.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/weiwenchen2022/typed"
+)
+
+var (
+	schemaFlag    = flag.Bool("schema", false, "treat the input as a JSON Schema instead of samples")
+	accessorsFlag = flag.Bool("accessors", false, "generate typed.M wrappers with accessor methods instead of structs")
+	typeName      = flag.String("type", "Root", "name of the root type")
+	packageName   = flag.String("package", "main", "package name of the generated file")
+	output        = flag.String("o", "", "write output to `file` (default standard output)")
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: typedgen [flags] [file ...]\n")
+	flag.PrintDefaults()
+	os.Exit(2)
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("typedgen: ")
+	flag.Usage = usage
+	flag.Parse()
+
+	values, err := readInputs(flag.Args())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var schema typed.M
+	if *schemaFlag {
+		if len(values) != 1 {
+			log.Fatalf("want exactly one schema, got %d documents", len(values))
+		}
+		var ok bool
+		if schema, ok = values[0].(typed.M); !ok {
+			log.Fatalf("schema is %T, not a JSON object", values[0])
+		}
+	} else {
+		if len(values) == 0 {
+			log.Fatal("no samples")
+		}
+		schema = typed.InferSchema(values...)
+	}
+
+	generate := generateStructs
+	if *accessorsFlag {
+		generate = generateAccessors
+	}
+	src, err := generate(*packageName, newModel(*typeName, schema))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *output == "" {
+		_, err = os.Stdout.Write(src)
+	} else {
+		err = os.WriteFile(*output, src, 0o644)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// readInputs reads the JSON documents held by the named files, or by standard
+// input if there are none.
+func readInputs(names []string) ([]any, error) {
+	if len(names) == 0 {
+		return readValues(os.Stdin)
+	}
+
+	var values []any
+	for _, name := range names {
+		f, err := os.Open(name)
+		if err != nil {
+			return nil, err
+		}
+		vs, err := readValues(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		values = append(values, vs...)
+	}
+	return values, nil
+}
+
+// readValues decodes the sequence of JSON documents read from r,
+// objects as typed.M and arrays as typed.A.
+func readValues(r io.Reader) ([]any, error) {
+	dec := json.NewDecoder(r)
+
+	var values []any
+	for {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); errors.Is(err, io.EOF) {
+			return values, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		var err error
+		switch raw = bytes.TrimSpace(raw); raw[0] {
+		case '{':
+			var m typed.M
+			err = json.Unmarshal(raw, &m)
+			values = append(values, m)
+		case '[':
+			var a typed.A
+			err = json.Unmarshal(raw, &a)
+			values = append(values, a)
+		default:
+			var v any
+			err = json.Unmarshal(raw, &v)
+			values = append(values, v)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
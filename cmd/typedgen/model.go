@@ -0,0 +1,237 @@
+package main
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/weiwenchen2022/typed"
+)
+
+// kind is the kind of Go type generated for a schema.
+type kind int
+
+const (
+	kindAny kind = iota
+	kindBool
+	kindInt
+	kindFloat
+	kindString
+	kindTime
+	kindMap
+	kindSlice
+	kindStruct
+)
+
+// goType describes the Go type generated for a schema.
+type goType struct {
+	kind     kind
+	elem     *goType     // element type, for kindSlice
+	strct    *structType // named type, for kindStruct
+	nullable bool
+}
+
+// structType is a named struct type generated for an object schema.
+type structType struct {
+	name   string
+	fields []*field
+}
+
+// field is a field of a generated struct type.
+type field struct {
+	name     string // Go field name
+	key      string // JSON object key
+	typ      *goType
+	required bool
+}
+
+// model is the set of named types generated for a root schema.
+type model struct {
+	name  string
+	root  *goType
+	types []*structType
+	names map[string]bool
+}
+
+// newModel builds the model for schema, naming its root type name.
+func newModel(name string, schema typed.M) *model {
+	m := &model{name: name, names: make(map[string]bool)}
+	m.root = m.typeOf(name, schema)
+	return m
+}
+
+func (m *model) typeOf(name string, schema typed.M) *goType {
+	types, nullable := schemaTypes(schema)
+
+	t := &goType{nullable: nullable}
+	switch {
+	case len(types) != 1:
+		t.kind = kindAny
+	case types[0] == "boolean":
+		t.kind = kindBool
+	case types[0] == "integer":
+		t.kind = kindInt
+	case types[0] == "number":
+		t.kind = kindFloat
+	case types[0] == "string":
+		t.kind = kindString
+		if format, _ := schema.StringValueOK("format"); format == "date-time" {
+			t.kind = kindTime
+		}
+	case types[0] == "array":
+		t.kind = kindSlice
+		items, ok := schema.DocumentOK("items")
+		if !ok {
+			items = typed.M{}
+		}
+		t.elem = m.typeOf(singular(name), items)
+	case types[0] == "object":
+		properties, ok := schema.DocumentOK("properties")
+		if !ok || len(properties) == 0 {
+			t.kind = kindMap
+			break
+		}
+		t.kind = kindStruct
+		t.strct = m.structOf(name, schema, properties)
+	}
+	return t
+}
+
+func (m *model) structOf(name string, schema, properties typed.M) *structType {
+	s := &structType{name: m.uniqueName(name)}
+	m.types = append(m.types, s)
+
+	required := make(map[string]bool)
+	if keys, ok := schema.ArrayOK("required"); ok {
+		for _, k := range keys {
+			if k, ok := k.(string); ok {
+				required[k] = true
+			}
+		}
+	}
+
+	fieldNames := make(map[string]bool)
+	for _, key := range properties.Keys() {
+		fieldName := goName(key)
+		for i := 2; fieldNames[fieldName]; i++ {
+			fieldName = goName(key) + strconv.Itoa(i)
+		}
+		fieldNames[fieldName] = true
+
+		fieldSchema, _ := properties[key].(typed.M)
+		s.fields = append(s.fields, &field{
+			name:     fieldName,
+			key:      key,
+			typ:      m.typeOf(s.name+fieldName, fieldSchema),
+			required: required[key],
+		})
+	}
+	return s
+}
+
+func (m *model) uniqueName(name string) string {
+	unique := name
+	for i := 2; m.names[unique]; i++ {
+		unique = name + strconv.Itoa(i)
+	}
+	m.names[unique] = true
+	return unique
+}
+
+// schemaTypes returns the sorted non-null types schema allows, and whether it
+// allows null.
+func schemaTypes(schema typed.M) (types []string, nullable bool) {
+	if t, ok := schema.StringValueOK("type"); ok {
+		types = []string{t}
+	} else if a, ok := schema.ArrayOK("type"); ok {
+		types, _ = a.StringsOK()
+	} else if schema.Exists("properties") {
+		types = []string{"object"}
+	}
+
+	var nonNull []string
+	for _, t := range types {
+		if t == "null" {
+			nullable = true
+		} else {
+			nonNull = append(nonNull, t)
+		}
+	}
+	sort.Strings(nonNull)
+
+	// An integer is a number, so a mix of both is just a number.
+	if len(nonNull) == 2 && nonNull[0] == "integer" && nonNull[1] == "number" {
+		nonNull = nonNull[1:]
+	}
+	return nonNull, nullable
+}
+
+// commonInitialisms are written in upper case in Go identifiers.
+var commonInitialisms = map[string]bool{
+	"API": true, "CPU": true, "CSS": true, "DNS": true, "HTML": true, "HTTP": true,
+	"HTTPS": true, "ID": true, "IP": true, "JSON": true, "SQL": true, "TLS": true,
+	"TTL": true, "UI": true, "URI": true, "URL": true, "UUID": true, "XML": true,
+}
+
+// goName converts a JSON key to an exported Go identifier.
+func goName(key string) string {
+	var words []string
+	var word []rune
+	flush := func() {
+		if len(word) > 0 {
+			words = append(words, string(word))
+			word = word[:0]
+		}
+	}
+
+	runes := []rune(key)
+	for i, r := range runes {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+			continue
+		case unicode.IsUpper(r) && len(word) > 0:
+			prev := word[len(word)-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || nextLower && unicode.IsUpper(prev) {
+				flush()
+			}
+		}
+		word = append(word, r)
+	}
+	flush()
+
+	var b strings.Builder
+	for _, w := range words {
+		if upper := strings.ToUpper(w); commonInitialisms[upper] {
+			b.WriteString(upper)
+			continue
+		}
+		rs := []rune(strings.ToLower(w))
+		rs[0] = unicode.ToUpper(rs[0])
+		b.WriteString(string(rs))
+	}
+
+	name := b.String()
+	if name == "" {
+		return "Field"
+	}
+	if r := []rune(name)[0]; !unicode.IsLetter(r) {
+		name = "X" + name
+	}
+	return name
+}
+
+// singular returns the name of the element type of a slice type named name.
+func singular(name string) string {
+	switch {
+	case strings.HasSuffix(name, "ies") && len(name) > 3:
+		return strings.TrimSuffix(name, "ies") + "y"
+	case strings.HasSuffix(name, "ss"):
+		return name + "Item"
+	case strings.HasSuffix(name, "s") && len(name) > 1:
+		return strings.TrimSuffix(name, "s")
+	}
+	return name + "Item"
+}
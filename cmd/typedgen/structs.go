@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+)
+
+// generateStructs returns the Go source of struct definitions for the model.
+func generateStructs(pkg string, m *model) ([]byte, error) {
+	var body bytes.Buffer
+	usesTime := false
+
+	typeExpr := func(t *goType) string {
+		expr := structFieldType(t)
+		if containsKind(t, kindTime) {
+			usesTime = true
+		}
+		return expr
+	}
+
+	if m.root.kind != kindStruct {
+		fmt.Fprintf(&body, "type %s %s\n\n", m.name, typeExpr(m.root))
+	}
+	for _, s := range m.types {
+		fmt.Fprintf(&body, "type %s struct {\n", s.name)
+		for _, f := range s.fields {
+			tag := f.key
+			if !f.required {
+				tag += ",omitempty"
+			}
+			fmt.Fprintf(&body, "\t%s %s `json:%q`\n", f.name, typeExpr(f.typ), tag)
+		}
+		fmt.Fprintf(&body, "}\n\n")
+	}
+
+	var src bytes.Buffer
+	fmt.Fprintf(&src, "// Code generated by typedgen; DO NOT EDIT.\n\npackage %s\n\n", pkg)
+	if usesTime {
+		fmt.Fprintf(&src, "import \"time\"\n\n")
+	}
+	src.Write(body.Bytes())
+	return format.Source(src.Bytes())
+}
+
+// structFieldType returns the Go type expression of t for a struct field.
+func structFieldType(t *goType) string {
+	var expr string
+	switch t.kind {
+	case kindBool:
+		expr = "bool"
+	case kindInt:
+		expr = "int64"
+	case kindFloat:
+		expr = "float64"
+	case kindString:
+		expr = "string"
+	case kindTime:
+		expr = "time.Time"
+	case kindStruct:
+		expr = t.strct.name
+	case kindMap:
+		return "map[string]any"
+	case kindSlice:
+		return "[]" + structFieldType(t.elem)
+	default:
+		return "any"
+	}
+	if t.nullable {
+		expr = "*" + expr
+	}
+	return expr
+}
+
+func containsKind(t *goType, k kind) bool {
+	for ; t != nil; t = t.elem {
+		if t.kind == k {
+			return true
+		}
+	}
+	return false
+}
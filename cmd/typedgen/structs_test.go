@@ -0,0 +1,96 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/weiwenchen2022/typed"
+)
+
+func TestGenerateStructs(t *testing.T) {
+	t.Parallel()
+
+	samples, err := readValues(strings.NewReader(`
+		{"order_id": 1, "total": 9.5, "created_at": "2023-08-17T17:37:08Z", "note": null,
+		 "items": [{"sku": "A1", "qty": 2}], "meta": {}}
+		{"order_id": 2, "total": 3, "created_at": "2023-08-18T09:00:00Z", "note": "gift",
+		 "items": [], "coupon": "SUMMER"}
+	`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	src, err := generateStructs("orders", newModel("Order", typed.InferSchema(samples...)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `// Code generated by typedgen; DO NOT EDIT.
+
+package orders
+
+import "time"
+
+type Order struct {
+	Coupon    string         ` + "`json:\"coupon,omitempty\"`" + `
+	CreatedAt time.Time      ` + "`json:\"created_at\"`" + `
+	Items     []OrderItem    ` + "`json:\"items\"`" + `
+	Meta      map[string]any ` + "`json:\"meta,omitempty\"`" + `
+	Note      *string        ` + "`json:\"note\"`" + `
+	OrderID   int64          ` + "`json:\"order_id\"`" + `
+	Total     float64        ` + "`json:\"total\"`" + `
+}
+
+type OrderItem struct {
+	Qty int64  ` + "`json:\"qty\"`" + `
+	Sku string ` + "`json:\"sku\"`" + `
+}
+`
+	if got := string(src); got != want {
+		t.Errorf("want:\n%s\ngot:\n%s", want, got)
+	}
+}
+
+func TestGenerateStructs_Schema(t *testing.T) {
+	t.Parallel()
+
+	values, err := readValues(strings.NewReader(`{
+		"type": "array",
+		"items": {"type": ["object", "null"], "properties": {"name": {"type": "string"}}}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	src, err := generateStructs("main", newModel("People", values[0].(typed.M)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, s := range []string{"type People []*PeopleItem\n", "type PeopleItem struct {\n", "Name string `json:\"name,omitempty\"`"} {
+		if !strings.Contains(string(src), s) {
+			t.Errorf("missing %q in:\n%s", s, src)
+		}
+	}
+}
+
+func TestGoName(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		key, want string
+	}{
+		{"name", "Name"},
+		{"user_id", "UserID"},
+		{"createdAt", "CreatedAt"},
+		{"HTTPServer", "HTTPServer"},
+		{"api-url", "APIURL"},
+		{"3d", "X3d"},
+		{"", "Field"},
+	}
+	for _, tc := range tests {
+		if got := goName(tc.key); got != tc.want {
+			t.Errorf("goName(%q): want %q; got %q", tc.key, tc.want, got)
+		}
+	}
+}
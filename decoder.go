@@ -0,0 +1,304 @@
+package typed
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Errors reported by a Decoder, wrapped in a *DecodeError.
+var (
+	ErrDuplicateKey = errors.New("duplicate key")
+	ErrMaxDepth     = errors.New("exceeds maximum depth")
+	ErrMaxBytes     = errors.New("exceeds maximum input size")
+	ErrMaxArrayLen  = errors.New("exceeds maximum array length")
+	ErrMaxStringLen = errors.New("exceeds maximum string length")
+)
+
+// A DecodeError describes a JSON value a Decoder rejected.
+type DecodeError struct {
+	Path   string // path of the value, in the syntax of the accessors; "" for the root
+	Offset int64  // input offset at which the error was detected
+	Err    error
+}
+
+func (e *DecodeError) Error() string {
+	if e.Path == "" {
+		return fmt.Sprintf("typed: %v (offset %d)", e.Err, e.Offset)
+	}
+	return fmt.Sprintf("typed: key %q: %v (offset %d)", e.Path, e.Err, e.Offset)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// A Decoder reads and decodes JSON values from an input stream into M, A and D,
+// optionally enforcing limits suitable for untrusted input. By default no limits are
+// enforced and duplicate object keys are accepted, the last one winning, just like
+// M.UnmarshalJSON does.
+type Decoder struct {
+	r   *limitReader
+	dec *json.Decoder
+
+	disallowDuplicateKeys bool
+	maxDepth              int
+	maxArrayLen           int
+	maxStringLen          int
+
+	path []string // escaped keys and indexes of the value being decoded
+}
+
+// NewDecoder returns a new decoder that reads from r.
+func NewDecoder(r io.Reader) *Decoder {
+	lr := &limitReader{r: r, n: -1}
+	dec := json.NewDecoder(lr)
+	return &Decoder{r: lr, dec: dec}
+}
+
+// UseNumber causes the Decoder to decode numbers as json.Number instead of float64.
+// The numeric accessors accept json.Number values.
+func (d *Decoder) UseNumber() {
+	d.dec.UseNumber()
+}
+
+// DisallowDuplicateKeys causes the Decoder to return an error wrapping ErrDuplicateKey
+// when an object holds the same key more than once.
+func (d *Decoder) DisallowDuplicateKeys() {
+	d.disallowDuplicateKeys = true
+}
+
+// SetMaxDepth limits the nesting depth of arrays and objects to n; the root
+// object or array has depth 1. A value of 0 means no limit.
+func (d *Decoder) SetMaxDepth(n int) {
+	d.maxDepth = n
+}
+
+// SetMaxBytes limits the total number of bytes the Decoder reads from its input to n.
+// A value of 0 means no limit.
+func (d *Decoder) SetMaxBytes(n int64) {
+	if n <= 0 {
+		d.r.n = -1
+		return
+	}
+	d.r.n = max(n-d.r.read, 0)
+}
+
+// SetMaxArrayLen limits the number of elements of every array to n. A value of 0
+// means no limit. The limit is checked as the elements are read, but the elements
+// themselves may be as large as the other limits allow.
+func (d *Decoder) SetMaxArrayLen(n int) {
+	d.maxArrayLen = n
+}
+
+// SetMaxStringLen limits the length in bytes of every string, including object keys,
+// to n. A value of 0 means no limit. The limit is checked once a string has been read
+// whole, so it doesn't bound the memory used to read it; use SetMaxBytes for that.
+func (d *Decoder) SetMaxStringLen(n int) {
+	d.maxStringLen = n
+}
+
+// Decode reads the next JSON value from its input and stores it in the value pointed
+// to by v, which must be a *M, *A, *D or *any. Objects are decoded as D when v is a *D
+// and as M otherwise; arrays are decoded as A.
+//
+// At the end of the input, Decode returns io.EOF. Other errors are reported as a
+// *DecodeError.
+func (d *Decoder) Decode(v any) error {
+	ordered := false
+	switch v.(type) {
+	case *M, *A, *any:
+	case *D:
+		ordered = true
+	default:
+		return fmt.Errorf("typed: cannot decode into %T", v)
+	}
+
+	d.path = d.path[:0]
+	tok, err := d.dec.Token()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return io.EOF
+		}
+		return d.error(err)
+	}
+
+	x, err := d.value(tok, 0, ordered)
+	if err != nil {
+		return err
+	}
+
+	switch p := v.(type) {
+	case *any:
+		*p = x
+		return nil
+	case *M:
+		if m, ok := x.(M); ok || x == nil {
+			*p = m
+			return nil
+		}
+	case *A:
+		if a, ok := x.(A); ok || x == nil {
+			*p = a
+			return nil
+		}
+	case *D:
+		if dd, ok := x.(D); ok || x == nil {
+			*p = dd
+			return nil
+		}
+	}
+	return d.error(fmt.Errorf("cannot decode %T into %T", x, v))
+}
+
+func (d *Decoder) value(tok json.Token, depth int, ordered bool) (any, error) {
+	switch x := tok.(type) {
+	default:
+		return tok, nil
+	case string:
+		if d.maxStringLen > 0 && len(x) > d.maxStringLen {
+			return nil, d.error(ErrMaxStringLen)
+		}
+		return x, nil
+	case json.Delim:
+		depth++
+		if d.maxDepth > 0 && depth > d.maxDepth {
+			return nil, d.error(ErrMaxDepth)
+		}
+		if x == '[' {
+			return d.array(depth, ordered)
+		}
+		return d.object(depth, ordered)
+	}
+}
+
+func (d *Decoder) array(depth int, ordered bool) (any, error) {
+	a := A{}
+	for d.dec.More() {
+		if d.maxArrayLen > 0 && len(a) == d.maxArrayLen {
+			return nil, d.error(ErrMaxArrayLen)
+		}
+
+		tok, err := d.dec.Token()
+		if err != nil {
+			return nil, d.error(err)
+		}
+		d.path = append(d.path, strconv.Itoa(len(a)))
+		v, err := d.value(tok, depth, ordered)
+		if err != nil {
+			return nil, err
+		}
+		d.path = d.path[:len(d.path)-1]
+		a = append(a, v)
+	}
+	if _, err := d.dec.Token(); err != nil {
+		return nil, d.error(err)
+	}
+	return a, nil
+}
+
+func (d *Decoder) object(depth int, ordered bool) (any, error) {
+	var m M
+	var od D
+	var seen map[string]int // indexes of the keys of od
+	if ordered {
+		od = D{}
+		seen = make(map[string]int)
+	} else {
+		m = M{}
+	}
+
+	for d.dec.More() {
+		tok, err := d.dec.Token()
+		if err != nil {
+			return nil, d.error(err)
+		}
+		key := tok.(string)
+		d.path = append(d.path, EscapeKey(key))
+		if d.maxStringLen > 0 && len(key) > d.maxStringLen {
+			return nil, d.error(ErrMaxStringLen)
+		}
+
+		j := -1
+		if ordered {
+			if i, ok := seen[key]; ok {
+				j = i
+			}
+		} else if _, ok := m[key]; ok {
+			j = 0
+		}
+		if j >= 0 && d.disallowDuplicateKeys {
+			return nil, d.error(ErrDuplicateKey)
+		}
+
+		if tok, err = d.dec.Token(); err != nil {
+			return nil, d.error(err)
+		}
+		v, err := d.value(tok, depth, ordered)
+		if err != nil {
+			return nil, err
+		}
+		d.path = d.path[:len(d.path)-1]
+
+		switch {
+		case !ordered:
+			m[key] = v
+		case j >= 0:
+			od[j].Value = v
+		default:
+			seen[key] = len(od)
+			od = append(od, E{key, v})
+		}
+	}
+	if _, err := d.dec.Token(); err != nil {
+		return nil, d.error(err)
+	}
+
+	if ordered {
+		return od, nil
+	}
+	return m, nil
+}
+
+func (d *Decoder) error(err error) error {
+	offset := d.dec.InputOffset()
+	if errors.Is(err, ErrMaxBytes) {
+		offset = d.r.read
+	} else if errors.Is(err, io.EOF) {
+		err = io.ErrUnexpectedEOF
+	}
+	return &DecodeError{Path: strings.Join(d.path, "."), Offset: offset, Err: err}
+}
+
+// limitReader reads from r until n bytes have been read, and reports ErrMaxBytes if
+// more input follows. A negative n means no limit.
+type limitReader struct {
+	r    io.Reader
+	n    int64
+	read int64
+}
+
+func (l *limitReader) Read(p []byte) (int, error) {
+	if l.n == 0 {
+		var b [1]byte
+		if k, err := l.r.Read(b[:]); k > 0 {
+			return 0, ErrMaxBytes
+		} else if err != nil {
+			return 0, err
+		}
+		return 0, nil
+	}
+
+	if l.n > 0 && int64(len(p)) > l.n {
+		p = p[:l.n]
+	}
+	k, err := l.r.Read(p)
+	l.read += int64(k)
+	if l.n > 0 {
+		l.n -= int64(k)
+	}
+	return k, err
+}
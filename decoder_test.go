@@ -0,0 +1,122 @@
+package typed
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestDecoder(t *testing.T) {
+	t.Parallel()
+
+	dec := NewDecoder(strings.NewReader(`
+		{"Name": "Wednesday", "Parents": ["Gomez", "Morticia"]}
+		["Pugsley"]
+		{"b": 1, "a": 2}
+	`))
+
+	var m M
+	if err := dec.Decode(&m); err != nil {
+		t.Fatal(err)
+	}
+	equal(t, "Wednesday", m.StringValue("Name"))
+	equal(t, "Morticia", m.StringValue("Parents.1"))
+
+	var a A
+	if err := dec.Decode(&a); err != nil {
+		t.Fatal(err)
+	}
+	equalSlice(t, []string{"Pugsley"}, a.Strings())
+
+	var d D
+	if err := dec.Decode(&d); err != nil {
+		t.Fatal(err)
+	}
+	equalSlice(t, []string{"b", "a"}, d.Keys())
+
+	equal(t, io.EOF, dec.Decode(&m))
+}
+
+func TestDecoder_UseNumber(t *testing.T) {
+	t.Parallel()
+
+	dec := NewDecoder(strings.NewReader(`{"id": 12345678901234567, "ratios": [0.5, 2]}`))
+	dec.UseNumber()
+
+	var m M
+	if err := dec.Decode(&m); err != nil {
+		t.Fatal(err)
+	}
+	equal(t, json.Number("12345678901234567"), m["id"].(json.Number))
+	equal(t, true, m.IsNumber("id"))
+	equal(t, 0.5, m.Float("ratios.0"))
+	equalSlice(t, []float64{0.5, 2}, m.Array("ratios").Floats())
+}
+
+func TestDecoder_Limits(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input  string
+		setup  func(*Decoder)
+		err    error
+		path   string
+		offset int64
+	}{
+		{`{"a": 1, "b": {"c": 2, "c": 3}}`, (*Decoder).DisallowDuplicateKeys, ErrDuplicateKey, "b.c", 26},
+		{`{"a.b": {"c": 2, "c": 3}}`, (*Decoder).DisallowDuplicateKeys, ErrDuplicateKey, `a\.b.c`, 20},
+		{`{"a": [[1]]}`, func(d *Decoder) { d.SetMaxDepth(2) }, ErrMaxDepth, "a.0", 8},
+		{`{"a": [1, 2, 3]}`, func(d *Decoder) { d.SetMaxArrayLen(2) }, ErrMaxArrayLen, "a", 11},
+		{`{"a": ["short", "too long"]}`, func(d *Decoder) { d.SetMaxStringLen(5) }, ErrMaxStringLen, "a.1", 26},
+		{`{"too long": 1}`, func(d *Decoder) { d.SetMaxStringLen(5) }, ErrMaxStringLen, "too long", 11},
+		{`{"a": "0123456789"}`, func(d *Decoder) { d.SetMaxBytes(10) }, ErrMaxBytes, "a", 10},
+	}
+	for _, tc := range tests {
+		dec := NewDecoder(strings.NewReader(tc.input))
+		tc.setup(dec)
+
+		var m M
+		err := dec.Decode(&m)
+		if !errors.Is(err, tc.err) {
+			t.Errorf("%s: want %v; got %v", tc.input, tc.err, err)
+			continue
+		}
+
+		var de *DecodeError
+		if !errors.As(err, &de) {
+			t.Errorf("%s: want *DecodeError; got %T", tc.input, err)
+			continue
+		}
+		equal(t, tc.path, de.Path)
+		equal(t, tc.offset, de.Offset)
+	}
+}
+
+func TestDecoder_MaxBytesExact(t *testing.T) {
+	t.Parallel()
+
+	input := `{"a": 1}`
+	dec := NewDecoder(strings.NewReader(input))
+	dec.SetMaxBytes(int64(len(input)))
+
+	var m M
+	if err := dec.Decode(&m); err != nil {
+		t.Fatal(err)
+	}
+	equal(t, 1, m.AsInt("a"))
+}
+
+func TestDecoder_Mismatch(t *testing.T) {
+	t.Parallel()
+
+	var m M
+	err := NewDecoder(strings.NewReader(`["Gomez"]`)).Decode(&m)
+	var de *DecodeError
+	equal(t, true, errors.As(err, &de))
+
+	err = NewDecoder(strings.NewReader(`{"a": }`)).Decode(&m)
+	var se *json.SyntaxError
+	equal(t, true, errors.As(err, &se))
+}
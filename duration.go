@@ -0,0 +1,235 @@
+package typed
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// A DurationParser parses the values of documents as durations: strings as by
+// ParseDuration, and numbers and numeric strings in a unit.
+type DurationParser struct {
+	// Unit is the unit of numbers. Seconds are used if it is zero.
+	Unit time.Duration
+}
+
+// defaultDurationParser is the DurationParser of the AsDuration accessors and
+// A.Durations, which read numbers as seconds. It is never modified; other parsers
+// are passed to AsDurationWith and A.DurationsWith.
+var defaultDurationParser = &DurationParser{Unit: time.Second}
+
+// Parse returns the duration v represents.
+func (p *DurationParser) Parse(v any) (time.Duration, error) {
+	unit := p.Unit
+	if unit == 0 {
+		unit = time.Second
+	}
+
+	switch x := v.(type) {
+	case string:
+		if f, err := strconv.ParseFloat(x, 64); err == nil {
+			return numberDuration(f, unit)
+		}
+		return ParseDuration(x)
+	case float64:
+		return numberDuration(x, unit)
+	case json.Number:
+		f, err := x.Float64()
+		if err != nil {
+			return 0, err
+		}
+		return numberDuration(f, unit)
+	}
+	_, err := convert[string](v)
+	return 0, err
+}
+
+// parse parses v, the result of a lookup failing with err.
+func (p *DurationParser) parse(v any, err error) (time.Duration, error) {
+	if err != nil {
+		return 0, err
+	}
+	return p.Parse(v)
+}
+
+func numberDuration(f float64, unit time.Duration) (time.Duration, error) {
+	d := math.Round(f * float64(unit))
+	if math.IsNaN(d) || math.Abs(d) >= math.MaxInt64 {
+		return 0, fmt.Errorf("duration %v out of range", f)
+	}
+	return time.Duration(d), nil
+}
+
+var durationUnits = map[string]time.Duration{
+	"ns": time.Nanosecond,
+	"us": time.Microsecond,
+	"µs": time.Microsecond, // U+00B5 micro sign
+	"μs": time.Microsecond, // U+03BC Greek letter mu
+	"ms": time.Millisecond,
+	"s":  time.Second,
+	"m":  time.Minute,
+	"h":  time.Hour,
+	"d":  24 * time.Hour,
+	"w":  7 * 24 * time.Hour,
+}
+
+// ambiguousUnits are the units of years and months, which have no fixed length.
+var ambiguousUnits = map[string]bool{
+	"y": true, "Y": true, "yr": true,
+	"M": true, "mo": true, "mon": true,
+}
+
+// ParseDuration parses a duration string, in the syntax of time.ParseDuration with
+// the additional units "d" for 24 hours and "w" for 7 days, such as "1d12h" or "2w",
+// or in the syntax of ISO 8601, such as "P1DT2H" or "PT1.5S". Days are 24 hours long.
+// As their length varies, years and months are reported as errors, whether as the
+// ISO 8601 designators "Y" and "M" or as the units "y", "mo" and "M".
+func ParseDuration(s string) (time.Duration, error) {
+	orig := s
+	neg := false
+	if s != "" && (s[0] == '-' || s[0] == '+') {
+		neg = s[0] == '-'
+		s = s[1:]
+	}
+
+	var (
+		d   time.Duration
+		err error
+	)
+	if strings.HasPrefix(s, "P") {
+		d, err = parseISODuration(orig, s[1:])
+	} else {
+		d, err = parseUnitDuration(orig, s)
+	}
+	if err != nil {
+		return 0, err
+	}
+	if neg {
+		d = -d
+	}
+	return d, nil
+}
+
+// parseUnitDuration parses s, orig without its sign, in the syntax of
+// time.ParseDuration.
+func parseUnitDuration(orig, s string) (time.Duration, error) {
+	if s == "0" {
+		return 0, nil
+	}
+	if s == "" {
+		return 0, fmt.Errorf("invalid duration %q", orig)
+	}
+
+	var d time.Duration
+	for s != "" {
+		i := strings.IndexFunc(s, func(r rune) bool { return !isDurationDigit(r) })
+		if i < 0 {
+			return 0, fmt.Errorf("missing unit in duration %q", orig)
+		}
+		j := strings.IndexFunc(s[i:], isDurationDigit)
+		if j < 0 {
+			j = len(s) - i
+		}
+		num, u := s[:i], s[i:i+j]
+		s = s[i+j:]
+
+		unit, ok := durationUnits[u]
+		if !ok && ambiguousUnits[u] {
+			return 0, fmt.Errorf("ambiguous duration %q: years and months have no fixed length", orig)
+		}
+		if !ok {
+			return 0, fmt.Errorf("unknown unit %q in duration %q", u, orig)
+		}
+		if d, ok = addDuration(d, num, unit); !ok {
+			return 0, fmt.Errorf("invalid duration %q", orig)
+		}
+	}
+	return d, nil
+}
+
+// parseISODuration parses s, orig without its sign and "P", in the syntax of
+// ISO 8601.
+func parseISODuration(orig, s string) (time.Duration, error) {
+	date, clock, hasT := strings.Cut(s, "T")
+	if date == "" && clock == "" || hasT && clock == "" {
+		return 0, fmt.Errorf("invalid ISO 8601 duration %q", orig)
+	}
+
+	d, err := isoComponents(orig, date, "YMWD", []time.Duration{0, 0, 7 * 24 * time.Hour, 24 * time.Hour})
+	if err != nil {
+		return 0, err
+	}
+	t, err := isoComponents(orig, clock, "HMS", []time.Duration{time.Hour, time.Minute, time.Second})
+	if err != nil {
+		return 0, err
+	}
+	if d+t < d {
+		return 0, fmt.Errorf("invalid ISO 8601 duration %q", orig)
+	}
+	return d + t, nil
+}
+
+// isoComponents returns the sum of the components of s, whose designators must appear
+// in the order of designators and have the corresponding units, zero for those of
+// no fixed length.
+func isoComponents(orig, s, designators string, units []time.Duration) (time.Duration, error) {
+	var d time.Duration
+	next := 0
+	for s != "" {
+		i := strings.IndexFunc(s, func(r rune) bool { return !isDurationDigit(r) && r != ',' })
+		if i < 0 {
+			return 0, fmt.Errorf("missing designator in ISO 8601 duration %q", orig)
+		}
+		num, designator := strings.Replace(s[:i], ",", ".", 1), s[i]
+		s = s[i+1:]
+
+		j := strings.IndexByte(designators[next:], designator)
+		if j < 0 {
+			return 0, fmt.Errorf("unexpected designator %q in ISO 8601 duration %q", designator, orig)
+		}
+		next += j + 1
+
+		unit := units[next-1]
+		if unit == 0 {
+			return 0, fmt.Errorf("ambiguous ISO 8601 duration %q: years and months have no fixed length", orig)
+		}
+		var ok bool
+		if d, ok = addDuration(d, num, unit); !ok {
+			return 0, fmt.Errorf("invalid ISO 8601 duration %q", orig)
+		}
+	}
+	return d, nil
+}
+
+func isDurationDigit(r rune) bool {
+	return '0' <= r && r <= '9' || r == '.'
+}
+
+// addDuration returns d plus the decimal number num in unit, and false if num is
+// malformed or the sum overflows.
+func addDuration(d time.Duration, num string, unit time.Duration) (time.Duration, bool) {
+	i, f, _ := strings.Cut(num, ".")
+	if i == "" && f == "" || strings.Trim(i+f, "0123456789") != "" {
+		return 0, false
+	}
+
+	var n int64
+	if i != "" {
+		var err error
+		if n, err = strconv.ParseInt(i, 10, 64); err != nil || n > math.MaxInt64/int64(unit) {
+			return 0, false
+		}
+	}
+	t := time.Duration(n) * unit
+	if f != "" {
+		frac, _ := strconv.ParseFloat("0."+f, 64)
+		t += time.Duration(math.Round(frac * float64(unit)))
+	}
+	if t < 0 || d+t < d {
+		return 0, false
+	}
+	return d + t, true
+}
@@ -0,0 +1,125 @@
+package typed
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestParseDuration(t *testing.T) {
+	t.Parallel()
+
+	const day = 24 * time.Hour
+	tests := map[string]time.Duration{
+		"0":             0,
+		"1h30m":         90 * time.Minute,
+		"1.5s":          1500 * time.Millisecond,
+		"-2m3.5ms":      -(2*time.Minute + 3500*time.Microsecond),
+		"300µs":         300 * time.Microsecond,
+		"1d":            day,
+		"1d12h":         36 * time.Hour,
+		"2w":            14 * day,
+		"0.5d":          12 * time.Hour,
+		"P1DT2H":        day + 2*time.Hour,
+		"PT1.5S":        1500 * time.Millisecond,
+		"PT0,5S":        500 * time.Millisecond,
+		"P2W":           14 * day,
+		"PT36H":         36 * time.Hour,
+		"-P1D":          -day,
+		"P1DT1H1M1.25S": day + time.Hour + time.Minute + 1250*time.Millisecond,
+	}
+	for s, want := range tests {
+		got, err := ParseDuration(s)
+		if err != nil {
+			t.Errorf("%s: %v", s, err)
+			continue
+		}
+		equal(t, want, got)
+	}
+
+	for _, s := range []string{"1h30m", "1.5s", "-2m3.5ms", "300µs", "1h1m1s1ms1us1ns"} {
+		want, _ := time.ParseDuration(s)
+		got, err := ParseDuration(s)
+		equal(t, nil, err)
+		equal(t, want, got)
+	}
+}
+
+func TestParseDuration_Errors(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"":           `invalid duration ""`,
+		"1":          `missing unit in duration "1"`,
+		"1y":         `ambiguous duration "1y": years and months have no fixed length`,
+		"1M":         `ambiguous duration "1M": years and months have no fixed length`,
+		"2w1mo":      `ambiguous duration "2w1mo": years and months have no fixed length`,
+		"1x":         `unknown unit "x" in duration "1x"`,
+		"1..5s":      `invalid duration "1..5s"`,
+		"P":          `invalid ISO 8601 duration "P"`,
+		"P1DT":       `invalid ISO 8601 duration "P1DT"`,
+		"P1M":        `ambiguous ISO 8601 duration "P1M": years and months have no fixed length`,
+		"P1Y2D":      `ambiguous ISO 8601 duration "P1Y2D": years and months have no fixed length`,
+		"PT1H1H":     `unexpected designator 'H' in ISO 8601 duration "PT1H1H"`,
+		"P1H":        `unexpected designator 'H' in ISO 8601 duration "P1H"`,
+		"PT1":        `missing designator in ISO 8601 duration "PT1"`,
+		"999999999w": `invalid duration "999999999w"`,
+	}
+	for s, want := range tests {
+		_, err := ParseDuration(s)
+		if err == nil {
+			t.Errorf("%s: no error", s)
+			continue
+		}
+		equal(t, want, err.Error())
+	}
+}
+
+func TestDurationParser(t *testing.T) {
+	t.Parallel()
+
+	p := &DurationParser{Unit: time.Millisecond}
+	for v, want := range map[any]time.Duration{
+		1500.0:             1500 * time.Millisecond,
+		json.Number("2.5"): 2500 * time.Microsecond,
+		"250":              250 * time.Millisecond,
+		"1d":               24 * time.Hour,
+		"PT1M":             time.Minute,
+	} {
+		got, err := p.Parse(v)
+		equal(t, nil, err)
+		equal(t, want, got)
+	}
+	_, err := p.Parse(true)
+	equal(t, true, err != nil)
+
+	m := M{"timeout": 30.0, "ttl": "P1D", "retries": A{"1s", 2.0, "PT3S"}, "every": "P1M"}
+	equal(t, 30*time.Second, m.AsDuration("timeout"))
+	equal(t, 24*time.Hour, m.AsDuration("ttl"))
+	equalSlice(t, []time.Duration{time.Second, 2 * time.Second, 3 * time.Second}, m.Array("retries").Durations())
+	_, ok := m.AsDurationOK("every")
+	equal(t, false, ok)
+	_, err = A{"1s", "P1Y"}.DurationsWith(defaultDurationParser)
+	equal(t, `element 1: ambiguous ISO 8601 duration "P1Y": years and months have no fixed length`, err.Error())
+	_, ok = A{"1s", "P1Y"}.DurationsOK()
+	equal(t, false, ok)
+	equal(t, true, panics(func() { A{"soon"}.Durations() }))
+
+	ds, err := A{"1s", 250.0}.DurationsWith(p)
+	equal(t, nil, err)
+	equalSlice(t, []time.Duration{time.Second, 250 * time.Millisecond}, ds)
+	d, err := m.AsDurationWith("timeout", p)
+	equal(t, nil, err)
+	equal(t, 30*time.Millisecond, d)
+	d, err = D{{"ttl", "2"}}.AsDurationWith("ttl", &DurationParser{Unit: time.Minute})
+	equal(t, nil, err)
+	equal(t, 2*time.Minute, d)
+	_, err = m.AsDurationWith("missing", p)
+	equal(t, `not found key "missing"`, err.Error())
+
+	l := NewLazyM([]byte(`{"nap": 90}`))
+	equal(t, 90*time.Second, l.AsDuration("nap"))
+	d, err = l.AsDurationWith("nap", p)
+	equal(t, nil, err)
+	equal(t, 90*time.Millisecond, d)
+}
@@ -0,0 +1,225 @@
+package typed
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// SetRaw returns a copy of the JSON value in data with the value for path, in the
+// syntax of the accessors, set to the JSON encoding of value. All other bytes are
+// kept as they are, including key order, whitespace and number formatting.
+//
+// Missing keys are inserted at the end of their object, creating the objects below
+// them as needed. A value for a key occurring more than once in an object replaces
+// the last one, which is the one the accessors read. Array indexes must exist. Syntax
+// errors are reported as a *DecodeError, and malformed paths as a *PathSyntaxError.
+// Slices of arrays are not supported.
+func SetRaw(data []byte, path string, value any) ([]byte, error) {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkRaw(data); err != nil {
+		return nil, err
+	}
+
+	segs, err := segments(path)
+	if err != nil {
+		return nil, err
+	}
+
+	s := scanner{data: data}
+	s.skipSpace()
+	for i, seg := range segs {
+		open := s.i
+		spans, isObject := s.spans()
+		if s.err != nil {
+			return nil, s.err
+		}
+
+		j := findSpan(spans, isObject, seg)
+		if j < 0 {
+			if !isObject {
+				return nil, fmt.Errorf("not found key %q", path[:seg.end])
+			}
+
+			// Insert the key with the objects holding the rest of the path.
+			for n := len(segs) - 1; n > i; n-- {
+				b = append(append(appendKey([]byte{'{'}, segs[n].key), b...), '}')
+			}
+			at, member := open+1, appendKey(nil, seg.key)
+			if len(spans) > 0 {
+				at, member = spans[len(spans)-1].end, append([]byte{','}, member...)
+			}
+			return splice(data, at, at, append(member, b...)), nil
+		}
+
+		s.i = spans[j].value
+	}
+
+	start := s.i
+	s.skip(0)
+	return splice(data, start, s.i, b), nil
+}
+
+// DeleteRaw returns a copy of the JSON value in data without the value for path, in
+// the syntax of the accessors, removing the comma separating it from its neighbours.
+// All other bytes are kept as they are. Every occurrence of a key occurring more than once
+// in an object is removed. If path doesn't exist, the copy is the same as data. Syntax
+// errors are reported as a *DecodeError, and malformed paths as a *PathSyntaxError.
+// Slices of arrays are not supported.
+func DeleteRaw(data []byte, path string) ([]byte, error) {
+	if err := checkRaw(data); err != nil {
+		return nil, err
+	}
+
+	segs, err := segments(path)
+	if err != nil {
+		return nil, err
+	}
+
+	data = bytes.Clone(data)
+	for {
+		s := scanner{data: data}
+		s.skipSpace()
+
+		var (
+			open     int
+			spans    []span
+			isObject bool
+			j        int
+		)
+		for _, seg := range segs {
+			if c := data[s.i]; c != '{' && c != '[' {
+				return data, nil
+			}
+
+			open = s.i
+			spans, isObject = s.spans()
+			if s.err != nil {
+				return nil, s.err
+			}
+			if j = findSpan(spans, isObject, seg); j < 0 {
+				return data, nil
+			}
+			s.i = spans[j].value
+		}
+
+		switch {
+		case j > 0:
+			data = splice(data, spans[j-1].end, spans[j].end, nil)
+		case len(spans) > 1:
+			data = splice(data, spans[0].start, spans[1].start, nil)
+		default:
+			data = splice(data, open+1, spans[0].end, nil)
+		}
+		if !isObject {
+			return data, nil
+		}
+	}
+}
+
+// A span holds the offsets of a member of an object or an element of an array in
+// the encoding.
+type span struct {
+	key   []byte
+	start int // start of the key, or of the element
+	value int // start of the value
+	end   int // end of the value
+}
+
+// spans scans the object or array at the current position, returning the offsets of
+// its members or elements, and reports whether it is an object. It fails for other
+// values.
+func (s *scanner) spans() ([]span, bool) {
+	if s.i >= len(s.data) {
+		s.failEOF()
+		return nil, false
+	}
+
+	end := byte('}')
+	switch c := s.data[s.i]; c {
+	case '{':
+	case '[':
+		end = ']'
+	default:
+		s.err = fmt.Errorf("unknown type %s", kindOfByte(c))
+		return nil, false
+	}
+
+	s.i++
+	s.skipSpace()
+	if s.i < len(s.data) && s.data[s.i] == end {
+		s.i++
+		return nil, end == '}'
+	}
+
+	var spans []span
+	for s.err == nil {
+		s.skipSpace()
+		sp := span{start: s.i}
+		if end == '}' {
+			k, ok := s.key()
+			if !ok {
+				return nil, false
+			}
+			sp.key = k
+		}
+		sp.value = s.i
+		s.skip(1)
+		sp.end = s.i
+		spans = append(spans, sp)
+		if !s.next(end) {
+			break
+		}
+	}
+	return spans, end == '}'
+}
+
+// findSpan returns the index of the last member of an object with the key of seg, or
+// of the element of an array seg refers to, or -1.
+func findSpan(spans []span, isObject bool, seg segment) int {
+	if !isObject {
+		i, ok := seg.element(len(spans))
+		if !ok {
+			return -1
+		}
+		return i
+	}
+
+	for j := len(spans) - 1; j >= 0; j-- {
+		if string(spans[j].key) == seg.key {
+			return j
+		}
+	}
+	return -1
+}
+
+// checkRaw checks the syntax of the JSON value in data.
+func checkRaw(data []byte) error {
+	s := scanner{data: data}
+	s.skipSpace()
+	s.skip(0)
+	if s.err == nil {
+		s.skipSpace()
+		if s.i < len(s.data) {
+			s.fail("invalid character %s after top-level value", quoteChar(s.data[s.i]))
+		}
+	}
+	return s.err
+}
+
+// appendKey appends k encoded as an object key, followed by a colon.
+func appendKey(b []byte, k string) []byte {
+	q, _ := json.Marshal(k)
+	return append(append(b, q...), ':')
+}
+
+// splice returns a copy of data with data[i:j] replaced by b.
+func splice(data []byte, i, j int, b []byte) []byte {
+	out := make([]byte, 0, len(data)-(j-i)+len(b))
+	out = append(out, data[:i]...)
+	out = append(out, b...)
+	return append(out, data[j:]...)
+}
@@ -0,0 +1,144 @@
+package typed
+
+import (
+	"errors"
+	"testing"
+)
+
+const editData = `{
+	"id": 1.50,
+	"trace": {},
+	"tags": ["a", "b", "c"],
+	"meta": {"type": "order.paid", "type": "order.refunded"}
+}`
+
+func TestSetRaw(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		path  string
+		value any
+		want  string
+	}{
+		{"id", 2, `{
+	"id": 2,
+	"trace": {},
+	"tags": ["a", "b", "c"],
+	"meta": {"type": "order.paid", "type": "order.refunded"}
+}`},
+		{"trace.id", "abc", `{
+	"id": 1.50,
+	"trace": {"id":"abc"},
+	"tags": ["a", "b", "c"],
+	"meta": {"type": "order.paid", "type": "order.refunded"}
+}`},
+		{"tags.1", M{"name": "B"}, `{
+	"id": 1.50,
+	"trace": {},
+	"tags": ["a", {"name":"B"}, "c"],
+	"meta": {"type": "order.paid", "type": "order.refunded"}
+}`},
+		{"meta.type", "order.shipped", `{
+	"id": 1.50,
+	"trace": {},
+	"tags": ["a", "b", "c"],
+	"meta": {"type": "order.paid", "type": "order.shipped"}
+}`},
+		{"span.parent.id", 7, `{
+	"id": 1.50,
+	"trace": {},
+	"tags": ["a", "b", "c"],
+	"meta": {"type": "order.paid", "type": "order.refunded"},"span":{"parent":{"id":7}}
+}`},
+	}
+	for _, tc := range tests {
+		got, err := SetRaw([]byte(editData), tc.path, tc.value)
+		if err != nil {
+			t.Errorf("%s: %v", tc.path, err)
+			continue
+		}
+		equal(t, tc.want, string(got))
+	}
+}
+
+func TestSetRaw_Errors(t *testing.T) {
+	t.Parallel()
+
+	_, err := SetRaw([]byte(editData), "tags.3", "d")
+	equal(t, `not found key "tags.3"`, err.Error())
+
+	_, err = SetRaw([]byte(editData), "id.value", 1)
+	equal(t, true, err != nil)
+
+	_, err = SetRaw([]byte(`{"a": 1,}`), "a", 2)
+	var de *DecodeError
+	equal(t, true, errors.As(err, &de))
+}
+
+func TestDeleteRaw(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		data string
+		path string
+		want string
+	}{
+		{editData, "id", `{
+	"trace": {},
+	"tags": ["a", "b", "c"],
+	"meta": {"type": "order.paid", "type": "order.refunded"}
+}`},
+		{editData, "meta", `{
+	"id": 1.50,
+	"trace": {},
+	"tags": ["a", "b", "c"]
+}`},
+		{editData, "tags.0", `{
+	"id": 1.50,
+	"trace": {},
+	"tags": ["b", "c"],
+	"meta": {"type": "order.paid", "type": "order.refunded"}
+}`},
+		{editData, "meta.type", `{
+	"id": 1.50,
+	"trace": {},
+	"tags": ["a", "b", "c"],
+	"meta": {}
+}`},
+		{editData, "trace.id", editData},
+		{editData, "id.value", editData},
+		{editData, "tags.5", editData},
+		{`{ "a": 1 }`, "a", `{ }`},
+	}
+	for _, tc := range tests {
+		got, err := DeleteRaw([]byte(tc.data), tc.path)
+		if err != nil {
+			t.Errorf("%s: %v", tc.path, err)
+			continue
+		}
+		equal(t, tc.want, string(got))
+	}
+}
+
+func TestEditRaw_PathGrammar(t *testing.T) {
+	t.Parallel()
+
+	data := []byte(`{"labels": {"app.kubernetes.io/name": "web"}, "items": [1, 2, 3]}`)
+	got, err := SetRaw(data, `labels["app.kubernetes.io/name"]`, "api")
+	if err != nil {
+		t.Fatal(err)
+	}
+	equal(t, `{"labels": {"app.kubernetes.io/name": "api"}, "items": [1, 2, 3]}`, string(got))
+
+	got, err = SetRaw(data, `labels.app\.kubernetes\.io/part-of`, "shop")
+	if err != nil {
+		t.Fatal(err)
+	}
+	equal(t, `{"labels": {"app.kubernetes.io/name": "web","app.kubernetes.io/part-of":"shop"}, "items": [1, 2, 3]}`, string(got))
+
+	got, err = DeleteRaw(data, "items.-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	equal(t, `{"labels": {"app.kubernetes.io/name": "web"}, "items": [1, 2]}`, string(got))
+}
@@ -0,0 +1,211 @@
+package typed
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// An EnumError reports a string outside of the allowed values.
+type EnumError struct {
+	Key     string // path of the value, if any
+	Value   string
+	Allowed []string
+}
+
+func (e *EnumError) Error() string {
+	allowed := make([]string, len(e.Allowed))
+	for i, s := range e.Allowed {
+		allowed[i] = strconv.Quote(s)
+	}
+	if e.Key == "" {
+		return fmt.Sprintf("invalid value %q: must be one of %s", e.Value, strings.Join(allowed, ", "))
+	}
+	return fmt.Sprintf("invalid value %q for key %q: must be one of %s", e.Value, e.Key, strings.Join(allowed, ", "))
+}
+
+// An Enum maps strings to the values of T, such as named constants:
+//
+//	var colors = typed.Enum[Color]{Values: map[string]Color{"red": Red, "green": Green}}
+//
+//	c := colors.Get(m, "color")
+type Enum[T any] struct {
+	Values map[string]T
+
+	// Fold makes the matching of strings case-insensitive. Values must then hold no
+	// two strings equal under case folding, such as "red" and "Red"; matching one of
+	// them is reported as an error.
+	Fold bool
+}
+
+// Parse returns the value s maps to, or an *EnumError.
+func (e Enum[T]) Parse(s string) (T, error) {
+	return e.parse("", s)
+}
+
+func (e Enum[T]) parse(key, s string) (T, error) {
+	var zero T
+	if !e.Fold {
+		if v, ok := e.Values[s]; ok {
+			return v, nil
+		}
+	} else {
+		var matches []string
+		for k := range e.Values {
+			if strings.EqualFold(k, s) {
+				matches = append(matches, k)
+			}
+		}
+		switch len(matches) {
+		case 1:
+			return e.Values[matches[0]], nil
+		case 0:
+		default:
+			slices.Sort(matches)
+			return zero, fmt.Errorf("typed: Enum values %q and %q are equal under case folding", matches[0], matches[1])
+		}
+	}
+
+	allowed := make([]string, 0, len(e.Values))
+	for k := range e.Values {
+		allowed = append(allowed, k)
+	}
+	slices.Sort(allowed)
+	return zero, &EnumError{Key: key, Value: s, Allowed: allowed}
+}
+
+// Get returns the value the string for given key within doc, an M, D, A or *LazyM,
+// maps to. It panics if the value is not a string or it is not one of the strings of
+// the Enum.
+func (e Enum[T]) Get(doc any, key string) T {
+	return must(e.GetErr(doc, key))
+}
+
+// GetOK is the same as Get, except it returns a boolean instead of panicking.
+func (e Enum[T]) GetOK(doc any, key string) (T, bool) {
+	v, err := e.GetErr(doc, key)
+	return v, err == nil
+}
+
+// GetErr is the same as Get, except it returns an error instead of panicking, an
+// *EnumError if the string is not one of the Enum.
+func (e Enum[T]) GetErr(doc any, key string) (T, error) {
+	var (
+		s   string
+		err error
+	)
+	if l, ok := doc.(*LazyM); ok {
+		s, err = lazyLookupErr[string](l, key)
+	} else {
+		s, err = lookupErr[string](doc, key)
+	}
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return e.parse(key, s)
+}
+
+// Slice returns the values the strings of a map to. It returns an *EnumError for the
+// first string which is not one of the Enum.
+func (e Enum[T]) Slice(a A) ([]T, error) {
+	s := make([]T, len(a))
+	for i := range a {
+		var err error
+		if s[i], err = e.GetErr(a, strconv.Itoa(i)); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// enumErr returns the string for given key within a, or an *EnumError if it is not
+// one of allowed.
+func enumErr(a any, key string, allowed []string) (string, error) {
+	s, err := lookupErr[string](a, key)
+	if err != nil {
+		return "", err
+	}
+	if !slices.Contains(allowed, s) {
+		return "", &EnumError{Key: key, Value: s, Allowed: allowed}
+	}
+	return s, nil
+}
+
+// AsEnum returns the string value for given key. It panics if the value is not one of
+// allowed.
+func (m M) AsEnum(key string, allowed ...string) string {
+	return must(enumErr(m, key, allowed))
+}
+
+// AsEnumOK is the same as AsEnum, except it returns a boolean instead of panicking.
+func (m M) AsEnumOK(key string, allowed ...string) (string, bool) {
+	s, err := enumErr(m, key, allowed)
+	return s, err == nil
+}
+
+// AsEnumErr is the same as AsEnum, except it returns an error instead of panicking,
+// an *EnumError if the value is a string other than allowed.
+func (m M) AsEnumErr(key string, allowed ...string) (string, error) {
+	return enumErr(m, key, allowed)
+}
+
+// AsEnum returns the string value for given key. It panics if the value is not one of
+// allowed.
+func (d D) AsEnum(key string, allowed ...string) string {
+	return must(enumErr(d, key, allowed))
+}
+
+// AsEnumOK is the same as AsEnum, except it returns a boolean instead of panicking.
+func (d D) AsEnumOK(key string, allowed ...string) (string, bool) {
+	s, err := enumErr(d, key, allowed)
+	return s, err == nil
+}
+
+// AsEnumErr is the same as AsEnum, except it returns an error instead of panicking,
+// an *EnumError if the value is a string other than allowed.
+func (d D) AsEnumErr(key string, allowed ...string) (string, error) {
+	return enumErr(d, key, allowed)
+}
+
+// AsEnum returns the string value for given key. It panics if the value is not one of
+// allowed.
+func (a A) AsEnum(key string, allowed ...string) string {
+	return must(enumErr(a, key, allowed))
+}
+
+// AsEnumOK is the same as AsEnum, except it returns a boolean instead of panicking.
+func (a A) AsEnumOK(key string, allowed ...string) (string, bool) {
+	s, err := enumErr(a, key, allowed)
+	return s, err == nil
+}
+
+// AsEnumErr is the same as AsEnum, except it returns an error instead of panicking,
+// an *EnumError if the value is a string other than allowed.
+func (a A) AsEnumErr(key string, allowed ...string) (string, error) {
+	return enumErr(a, key, allowed)
+}
+
+// AsEnums returns the slice of string the array represents. It panics if one of
+// elements is not one of allowed.
+func (a A) AsEnums(allowed ...string) []string {
+	return must(a.enums(allowed))
+}
+
+// AsEnumsOK is the same as AsEnums, except it returns a boolean instead of panicking.
+func (a A) AsEnumsOK(allowed ...string) ([]string, bool) {
+	s, err := a.enums(allowed)
+	return s, err == nil
+}
+
+func (a A) enums(allowed []string) ([]string, error) {
+	s := make([]string, len(a))
+	for i := range a {
+		var err error
+		if s[i], err = enumErr(a, strconv.Itoa(i), allowed); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
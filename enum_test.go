@@ -0,0 +1,89 @@
+package typed
+
+import (
+	"errors"
+	"testing"
+)
+
+type color int
+
+const (
+	red color = iota + 1
+	green
+)
+
+var colors = Enum[color]{Values: map[string]color{"red": red, "green": green}}
+
+func TestAsEnum(t *testing.T) {
+	t.Parallel()
+
+	m := M{"mode": "fast", "level": "loud", "count": 1.0, "modes": A{"fast", "slow"}}
+	equal(t, "fast", m.AsEnum("mode", "fast", "slow"))
+	equal(t, "fast", D{{"mode", "fast"}}.AsEnum("mode", "fast", "slow"))
+	equalSlice(t, []string{"fast", "slow"}, m.Array("modes").AsEnums("fast", "slow"))
+
+	_, err := m.AsEnumErr("level", "quiet", "normal")
+	var ee *EnumError
+	equal(t, true, errors.As(err, &ee))
+	equal(t, "loud", ee.Value)
+	equal(t, `invalid value "loud" for key "level": must be one of "quiet", "normal"`, err.Error())
+
+	_, err = m.AsEnumErr("count", "one")
+	equal(t, false, errors.As(err, &ee))
+	_, ok := m.AsEnumOK("missing", "fast")
+	equal(t, false, ok)
+	_, ok = m.Array("modes").AsEnumsOK("fast")
+	equal(t, false, ok)
+	equal(t, true, panics(func() { A{"slow"}.AsEnum("0", "fast") }))
+}
+
+func TestEnum(t *testing.T) {
+	t.Parallel()
+
+	m := M{"color": "green", "shade": "Red", "palette": A{"red", "green"}, "bad": A{"red", "blue"}}
+	equal(t, green, colors.Get(m, "color"))
+	_, ok := colors.GetOK(m, "shade")
+	equal(t, false, ok)
+
+	folded := colors
+	folded.Fold = true
+	equal(t, red, folded.Get(m, "shade"))
+
+	got, err := colors.Slice(m.Array("palette"))
+	equal(t, nil, err)
+	equalSlice(t, []color{red, green}, got)
+
+	_, err = colors.Slice(m.Array("bad"))
+	equal(t, `invalid value "blue" for key "1": must be one of "green", "red"`, err.Error())
+
+	_, err = colors.Parse("blue")
+	equal(t, `invalid value "blue": must be one of "green", "red"`, err.Error())
+
+	_, err = colors.GetErr(m, "missing")
+	equal(t, `not found key "missing"`, err.Error())
+	_, err = colors.GetErr(m, "palette")
+	equal(t, "interface conversion: interface {} is typed.A, not string", err.Error())
+	equal(t, true, panics(func() { colors.Get(NewLazyM([]byte(`{"color": "pink"}`)), "color") }))
+	equal(t, red, colors.Get(NewLazyM([]byte(`{"color": "red"}`)), "color"))
+
+	equal(t, green, colors.Get(D{{"color", "green"}}, "color"))
+	equal(t, red, colors.Get(A{M{"color": "red"}}, "0.color"))
+	_, err = colors.GetErr(D{{"shade", "red"}}, "color")
+	equal(t, `not found key "color"`, err.Error())
+}
+
+func TestEnum_FoldCollision(t *testing.T) {
+	t.Parallel()
+
+	e := Enum[int]{Values: map[string]int{"red": 1, "Red": 2, "green": 3}, Fold: true}
+	for range 10 {
+		_, err := e.Parse("RED")
+		equal(t, `typed: Enum values "Red" and "red" are equal under case folding`, err.Error())
+		_, err = e.Parse("red")
+		equal(t, true, err != nil)
+	}
+	equal(t, 3, e.Get(M{"c": "GREEN"}, "c"))
+
+	e.Fold = false
+	equal(t, 2, e.Get(M{"c": "Red"}, "c"))
+}
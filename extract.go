@@ -0,0 +1,186 @@
+package typed
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Extract returns the values for paths, in the syntax of the accessors, within the
+// JSON value in data, scanning data once. Subtrees holding none of the paths are
+// skipped without being parsed. The result maps each path found to its value, such as
+// m["meta.type"]; paths not found are left out. Arrays holding negative indexes or
+// slices of the paths are parsed. Syntax errors are reported as a *DecodeError, and
+// malformed paths as a *PathSyntaxError.
+func Extract(data []byte, paths ...string) (M, error) {
+	root := &pathNode{}
+	for _, path := range paths {
+		segs, err := segments(path)
+		if err != nil {
+			return nil, err
+		}
+
+		n := root
+		for _, seg := range segs {
+			c, ok := n.children[seg.key]
+			if !ok {
+				if n.children == nil {
+					n.children = make(map[string]*pathNode)
+				}
+				c = &pathNode{seg: seg}
+				n.children[seg.key] = c
+			}
+			if seg.slice || seg.ok && strconv.Itoa(seg.index) != seg.key {
+				n.parse = true
+			}
+			n = c
+		}
+		n.paths = append(n.paths, path)
+	}
+
+	m := M{}
+	s := scanner{data: data, p: &Parser{}}
+	s.skipSpace()
+	s.extract(root, m, 0)
+	if s.err == nil {
+		s.skipSpace()
+		if s.i < len(s.data) {
+			s.fail("invalid character %s after top-level value", quoteChar(s.data[s.i]))
+		}
+	}
+	if s.err != nil {
+		return nil, s.err
+	}
+	return m, nil
+}
+
+// Get returns the value for path, in the syntax of the accessors, within the JSON
+// value in data. Only the value found is parsed.
+func Get(data []byte, path string) (any, error) {
+	raw, err := findRaw(data, path)
+	if err != nil {
+		return nil, err
+	}
+	var p Parser
+	return p.Parse(raw)
+}
+
+// A pathNode is a node of the tree of the paths to extract.
+type pathNode struct {
+	seg      segment
+	children map[string]*pathNode
+
+	// parse reports whether the value of the node is parsed to find its children,
+	// which can't be found by scanning, such as negative indexes.
+	parse bool
+
+	// paths holds the paths ending at the node.
+	paths []string
+}
+
+// extract scans the value at the current position, storing in m the values for the
+// paths below n.
+func (s *scanner) extract(n *pathNode, m M, depth int) {
+	if s.i >= len(s.data) {
+		s.failEOF()
+		return
+	}
+
+	if len(n.paths) > 0 || n.parse {
+		v := s.value(depth)
+		if s.err != nil {
+			return
+		}
+		for _, path := range n.paths {
+			m[path] = v
+		}
+		extractFrom(v, n, m)
+		return
+	}
+
+	switch c := s.data[s.i]; c {
+	case '{', '[':
+		if depth >= maxNestingDepth {
+			s.fail("exceeded max depth")
+			return
+		}
+	default:
+		s.skip(depth)
+		return
+	}
+
+	end := byte('}')
+	if s.data[s.i] == '[' {
+		end = ']'
+	}
+	s.i++
+	s.skipSpace()
+	if s.i < len(s.data) && s.data[s.i] == end {
+		s.i++
+		return
+	}
+
+	var buf [20]byte
+	for i := 0; s.err == nil; i++ {
+		s.skipSpace()
+		var c *pathNode
+		if end == '}' {
+			k, ok := s.key()
+			if !ok {
+				return
+			}
+			c = n.children[string(k)]
+		} else {
+			c = n.children[string(strconv.AppendInt(buf[:0], int64(i), 10))]
+		}
+
+		if c != nil {
+			s.extract(c, m, depth+1)
+		} else {
+			s.skip(depth + 1)
+		}
+		if !s.next(end) {
+			return
+		}
+	}
+}
+
+// extractFrom stores in m the values for the paths below n within v, a value
+// already parsed.
+func extractFrom(v any, n *pathNode, m M) {
+	for _, c := range n.children {
+		cv, result := child(v, c.seg)
+		if result != lookupFound {
+			continue
+		}
+
+		for _, path := range c.paths {
+			m[path] = cv
+		}
+		extractFrom(cv, c, m)
+	}
+}
+
+// findRaw returns the raw encoding of the value for key within the JSON value in
+// data.
+func findRaw(data []byte, key string) ([]byte, error) {
+	segs, err := segments(key)
+	if err != nil {
+		return nil, err
+	}
+
+	s := scanner{data: data}
+	s.skipSpace()
+	if !s.find(segs) {
+		if s.err != nil {
+			return nil, s.err
+		}
+		return nil, fmt.Errorf("not found key %q", key)
+	}
+
+	start := s.i
+	s.skip(0)
+	if s.err != nil {
+		return nil, s.err
+	}
+	return data[start:s.i], nil
+}
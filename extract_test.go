@@ -0,0 +1,113 @@
+package typed
+
+import (
+	"errors"
+	"testing"
+)
+
+var extractData = []byte(`{
+	"meta": {"type": "order.paid", "tenant": "acme", "tags": ["a", "b"]},
+	"items": [{"sku": "x1", "qty": 2}, {"sku": "y2", "qty": 1}],
+	"body": {"large": [1, 2, 3, {"nested": true}]},
+	"meta": {"type": "order.refunded", "tenant": "acme", "tags": ["a", "b"]}
+}`)
+
+func TestExtract(t *testing.T) {
+	t.Parallel()
+
+	m, err := Extract(extractData, "meta.type", "meta.tenant", "items.1.sku", "meta.tags", "meta.tags.0", "missing", "items.5")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	equal(t, 5, len(m))
+	equal(t, "order.refunded", m["meta.type"].(string))
+	equal(t, "acme", m["meta.tenant"].(string))
+	equal(t, "y2", m["items.1.sku"].(string))
+	equalSlice(t, A{"a", "b"}, m["meta.tags"].(A))
+	equal(t, "a", m["meta.tags.0"].(string))
+}
+
+func TestExtract_Errors(t *testing.T) {
+	t.Parallel()
+
+	for _, input := range []string{
+		``,
+		`{"meta": {"type": "x"}, "body": [1, 2}`,
+		`{"meta": {"type": tru}}`,
+		`{"meta": {}} x`,
+	} {
+		_, err := Extract([]byte(input), "meta.type")
+		var de *DecodeError
+		if !errors.As(err, &de) {
+			t.Errorf("%q: want *DecodeError; got %v", input, err)
+		}
+	}
+}
+
+func TestGet(t *testing.T) {
+	t.Parallel()
+
+	v, err := Get(extractData, "items.0.qty")
+	if err != nil {
+		t.Fatal(err)
+	}
+	equal(t, 2.0, v.(float64))
+
+	v, err = Get(extractData, "body")
+	if err != nil {
+		t.Fatal(err)
+	}
+	equal(t, true, v.(M).Bool("large.3.nested"))
+
+	v, err = Get([]byte(`[{"id": 1}]`), "0.id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	equal(t, 1.0, v.(float64))
+
+	_, err = Get(extractData, "meta.missing")
+	equal(t, `not found key "meta.missing"`, err.Error())
+}
+
+func BenchmarkExtract(b *testing.B) {
+	data := []byte(`{"meta": {"type": "order.paid", "tenant": "acme"}, "events": ` + string(benchmarkEvents(1000)) + `}`)
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(data)))
+	for i := 0; i < b.N; i++ {
+		if _, err := Extract(data, "meta.type", "meta.tenant"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestExtract_PathGrammar(t *testing.T) {
+	t.Parallel()
+
+	data := []byte(`{"labels": {"app.kubernetes.io/name": "web"}, "items": [1, 2, 3, 4]}`)
+	m, err := Extract(data, `labels["app.kubernetes.io/name"]`, "items.-1", "items.1:3", "items.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	equal(t, "web", m[`labels["app.kubernetes.io/name"]`].(string))
+	equal(t, 4.0, m["items.-1"].(float64))
+	equalSlice(t, A{2.0, 3.0}, m["items.1:3"].(A))
+	equal(t, 1.0, m["items.0"].(float64))
+
+	v, err := Get(data, `labels.app\.kubernetes\.io/name`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	equal(t, "web", v.(string))
+
+	v, err = Get(data, "items.-2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	equal(t, 3.0, v.(float64))
+
+	_, err = Extract(data, `labels["x`)
+	var pe *PathSyntaxError
+	equal(t, true, errors.As(err, &pe))
+}
@@ -0,0 +1,173 @@
+package typed
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Flatten returns the values of the document keyed by their paths, in the syntax of
+// the accessors, with the keys escaped and the indexes of arrays as segments, such as
+// "items.0.id". Only scalars and empty documents and arrays, as M, D and A, are kept as
+// values.
+func (m M) Flatten() map[string]any {
+	flat := make(map[string]any)
+	flatten(flat, "", true, m, ".", EscapeKey)
+	return flat
+}
+
+// FlattenSep is the same as Flatten, except the keys are joined with sep and are not
+// escaped, such as "items/0/id" for the separator "/". Keys holding sep are not
+// unflattened back to themselves.
+func (m M) FlattenSep(sep string) map[string]any {
+	flat := make(map[string]any)
+	flatten(flat, "", true, m, sep, func(k string) string { return k })
+	return flat
+}
+
+// flatten adds the values within v to flat. The prefix of the root is empty, as is that
+// of a value whose key is empty, which root tells apart.
+func flatten(flat map[string]any, prefix string, root bool, v any, sep string, escape func(string) string) {
+	join := func(key string) string {
+		if root {
+			return key
+		}
+		return prefix + sep + key
+	}
+
+	var n int
+	switch x := v.(type) {
+	case M:
+		n = len(x)
+		for k, v := range x {
+			flatten(flat, join(escape(k)), false, v, sep, escape)
+		}
+	case map[string]any:
+		n = len(x)
+		for k, v := range x {
+			flatten(flat, join(escape(k)), false, v, sep, escape)
+		}
+	case D:
+		n = len(x)
+		for _, e := range x {
+			flatten(flat, join(escape(e.Key)), false, e.Value, sep, escape)
+		}
+	case A:
+		n = len(x)
+		for i, v := range x {
+			flatten(flat, join(strconv.Itoa(i)), false, v, sep, escape)
+		}
+	case []any:
+		n = len(x)
+		for i, v := range x {
+			flatten(flat, join(strconv.Itoa(i)), false, v, sep, escape)
+		}
+	default:
+		flat[prefix] = v
+		return
+	}
+	if n == 0 && !root {
+		switch x := v.(type) {
+		case map[string]any:
+			v = M(x)
+		case []any:
+			v = A(x)
+		}
+		flat[prefix] = v
+	}
+}
+
+// Unflatten rebuilds the document flattened by Flatten. The keys are paths in the
+// syntax of the accessors; documents whose keys are the indexes 0 to n-1 become
+// arrays, except the document returned. An error is returned if a path is malformed,
+// or if a value is also the parent of other values, as with "a" and "a.b".
+func Unflatten(flat map[string]any) (M, error) {
+	return unflatten(flat, segments)
+}
+
+// UnflattenSep is the same as Unflatten, except the keys are split at sep, as
+// flattened by FlattenSep, instead of being parsed as paths.
+func UnflattenSep(flat map[string]any, sep string) (M, error) {
+	return unflatten(flat, func(path Path) ([]segment, error) {
+		keys := strings.Split(path, sep)
+		segs := make([]segment, len(keys))
+		for i, k := range keys {
+			segs[i] = newSegment(k, 0)
+		}
+		return segs, nil
+	})
+}
+
+// flatNode is a value being unflattened.
+type flatNode struct {
+	children map[string]*flatNode
+	keyed    bool // some child is not an array index
+	value    any
+	leaf     bool
+}
+
+func unflatten(flat map[string]any, split func(Path) ([]segment, error)) (M, error) {
+	root := &flatNode{children: make(map[string]*flatNode)}
+	// Sorting the paths makes the error returned for conflicting paths deterministic.
+	for _, path := range M(flat).Keys() {
+		segs, err := split(path)
+		if err != nil {
+			return nil, err
+		}
+
+		n := root
+		for _, seg := range segs {
+			if n.leaf {
+				return nil, fmt.Errorf("conflicting key %q", path)
+			}
+			if n.children == nil {
+				n.children = make(map[string]*flatNode)
+			}
+			c, ok := n.children[seg.key]
+			if !ok {
+				c = &flatNode{}
+				n.children[seg.key] = c
+				n.keyed = n.keyed || !seg.ok || seg.slice || seg.index < 0 || strconv.Itoa(seg.index) != seg.key
+			}
+			n = c
+		}
+		if n.leaf || n.children != nil {
+			return nil, fmt.Errorf("conflicting key %q", path)
+		}
+		n.value, n.leaf = flat[path], true
+	}
+
+	m := make(M, len(root.children))
+	for k, c := range root.children {
+		m[k] = c.build()
+	}
+	return m, nil
+}
+
+func (n *flatNode) build() any {
+	if n.leaf {
+		return n.value
+	}
+
+	if !n.keyed {
+		a := make(A, len(n.children))
+		for k, c := range n.children {
+			i, _ := strconv.Atoi(k)
+			if i >= len(a) {
+				// Indexes missing: keep the document.
+				a = nil
+				break
+			}
+			a[i] = c.build()
+		}
+		if a != nil {
+			return a
+		}
+	}
+
+	m := make(M, len(n.children))
+	for k, c := range n.children {
+		m[k] = c.build()
+	}
+	return m
+}
@@ -0,0 +1,124 @@
+package typed
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestFlatten(t *testing.T) {
+	t.Parallel()
+
+	var m M
+	err := json.Unmarshal([]byte(`{
+		"id": 1,
+		"items": [{"sku": "a", "qty": 2}, {"sku": "b"}],
+		"labels": {"app.kubernetes.io/name": "web"},
+		"notes": [],
+		"meta": {},
+		"pet": null
+	}`), &m)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	flat := m.Flatten()
+	want := map[string]any{
+		"id":                              1.0,
+		"items.0.sku":                     "a",
+		"items.0.qty":                     2.0,
+		"items.1.sku":                     "b",
+		`labels.app\.kubernetes\.io/name`: "web",
+		"notes":                           A{},
+		"meta":                            M{},
+		"pet":                             nil,
+	}
+	equal(t, true, reflect.DeepEqual(want, flat))
+	for k := range flat {
+		_, _, result := walk(m, k)
+		equal(t, lookupFound, result)
+	}
+
+	got, err := Unflatten(flat)
+	if err != nil {
+		t.Fatal(err)
+	}
+	equal(t, "b", got.StringValue("items.1.sku"))
+	equal(t, "web", got.StringValue(`labels["app.kubernetes.io/name"]`))
+	equal(t, 0, len(got.Array("notes")))
+	pet, ok := got["pet"]
+	equal(t, true, ok)
+	equal(t, nil, pet)
+
+	flat = m.FlattenSep("/")
+	equal(t, "web", flat["labels/app.kubernetes.io/name"])
+	equal(t, "a", flat["items/0/sku"])
+	got, err = UnflattenSep(flat, "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	equal(t, 2, got.AsInt("items.0.qty"))
+	// Keys holding the separator are split.
+	equal(t, "web", got.StringValue(`labels["app.kubernetes.io"].name`))
+}
+
+func TestFlatten_EmptyKey(t *testing.T) {
+	t.Parallel()
+
+	m := M{"": M{"x": 1.0, "": M{}}, "x": 2.0, "a": M{"": 3.0}}
+	flat := m.Flatten()
+	want := map[string]any{
+		".x": 1.0,
+		".":  M{},
+		"x":  2.0,
+		"a.": 3.0,
+	}
+	equal(t, true, reflect.DeepEqual(want, flat))
+
+	got, err := Unflatten(flat)
+	if err != nil {
+		t.Fatal(err)
+	}
+	equal(t, true, reflect.DeepEqual(m, got))
+
+	got, err = UnflattenSep(m.FlattenSep("/"), "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	equal(t, true, reflect.DeepEqual(m, got))
+}
+
+func TestUnflatten(t *testing.T) {
+	t.Parallel()
+
+	got, err := Unflatten(map[string]any{
+		"0":        "root keys stay keys",
+		"a.1":      "b",
+		"a.0":      "a",
+		"gaps.0":   1.0,
+		"gaps.2":   3.0,
+		"codes.01": "x",
+		"neg.-1":   "y",
+		`q["0"]`:   "z",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	equal(t, "root keys stay keys", got.StringValue("0"))
+	equalSlice(t, []string{"a", "b"}, got.Array("a").Strings())
+	equal(t, 3.0, got.Document("gaps").Float("2"))
+	equal(t, "x", got.Document("codes")["01"])
+	equal(t, "y", got.Document("neg")["-1"])
+	equal(t, "z", got.Document("q")["0"])
+
+	for _, flat := range []map[string]any{
+		{"a": 1, "a.b": 2},
+		{"a.b": 1, "a.b.c": 2},
+		{`a["b`: 1},
+	} {
+		_, err := Unflatten(flat)
+		equal(t, true, err != nil)
+	}
+	_, err = Unflatten(map[string]any{"a": 1, "a.b": 2})
+	equal(t, `conflicting key "a.b"`, err.Error())
+}
@@ -0,0 +1,100 @@
+package typed
+
+import (
+	"iter"
+
+	"golang.org/x/exp/constraints"
+)
+
+// All returns an iterator over the keys and values of the document, in sorted key
+// order.
+func (m M) All() iter.Seq2[string, any] {
+	return func(yield func(string, any) bool) {
+		for _, k := range m.Keys() {
+			if !yield(k, m[k]) {
+				return
+			}
+		}
+	}
+}
+
+// All returns an iterator over the keys and values of the document, in document
+// order.
+func (d D) All() iter.Seq2[string, any] {
+	return func(yield func(string, any) bool) {
+		for _, e := range d {
+			if !yield(e.Key, e.Value) {
+				return
+			}
+		}
+	}
+}
+
+// All returns an iterator over the indexes and elements of the array.
+func (a A) All() iter.Seq2[int, any] {
+	return func(yield func(int, any) bool) {
+		for i, v := range a {
+			if !yield(i, v) {
+				return
+			}
+		}
+	}
+}
+
+// BoolsSeq returns an iterator over the booleans the array represents. The
+// iteration stops before the first element of a JSON type other than boolean.
+func (a A) BoolsSeq() iter.Seq[bool] {
+	return seq[bool](a)
+}
+
+// AsIntsSeq returns an iterator over the ints the array represents. The iteration
+// stops before the first element of a JSON type other than number.
+func (a A) AsIntsSeq() iter.Seq[int] {
+	return numericSeq[int](a)
+}
+
+// AsInt64sSeq returns an iterator over the int64s the array represents. The
+// iteration stops before the first element of a JSON type other than number.
+func (a A) AsInt64sSeq() iter.Seq[int64] {
+	return numericSeq[int64](a)
+}
+
+// FloatsSeq returns an iterator over the float64s the array represents. The
+// iteration stops before the first element of a JSON type other than number.
+func (a A) FloatsSeq() iter.Seq[float64] {
+	return numericSeq[float64](a)
+}
+
+// StringsSeq returns an iterator over the strings the array represents. The
+// iteration stops before the first element of a JSON type other than string.
+func (a A) StringsSeq() iter.Seq[string] {
+	return seq[string](a)
+}
+
+// DocumentsSeq returns an iterator over the JSON documents the array represents.
+// The iteration stops before the first element of a JSON type other than document.
+func (a A) DocumentsSeq() iter.Seq[M] {
+	return seq[M](a)
+}
+
+func seq[E any](a []any) iter.Seq[E] {
+	return func(yield func(E) bool) {
+		for _, v := range a {
+			e, ok := v.(E)
+			if !ok || !yield(e) {
+				return
+			}
+		}
+	}
+}
+
+func numericSeq[E constraints.Integer | constraints.Float](a []any) iter.Seq[E] {
+	return func(yield func(E) bool) {
+		for _, v := range a {
+			f, ok := toFloat(v)
+			if !ok || !yield(E(f)) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package typed
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestM_All(t *testing.T) {
+	t.Parallel()
+
+	m := M{"b": 2.0, "a": 1.0, "c": 3.0}
+	var keys []string
+	for k, v := range m.All() {
+		keys = append(keys, k)
+		equal(t, m[k], v)
+		if k == "b" {
+			break
+		}
+	}
+	equalSlice(t, []string{"a", "b"}, keys)
+}
+
+func TestD_All(t *testing.T) {
+	t.Parallel()
+
+	d := D{{"b", 2.0}, {"a", 1.0}}
+	var keys []string
+	for k := range d.All() {
+		keys = append(keys, k)
+	}
+	equalSlice(t, []string{"b", "a"}, keys)
+}
+
+func TestA_All(t *testing.T) {
+	t.Parallel()
+
+	a := A{"x", "y"}
+	for i, v := range a.All() {
+		equal(t, a[i], v)
+	}
+}
+
+func TestA_Seq(t *testing.T) {
+	t.Parallel()
+
+	equalSlice(t, []string{"a", "b"}, slices.Collect(A{"a", "b", 3.0, "d"}.StringsSeq()))
+	equalSlice(t, []int{1, 2}, slices.Collect(A{1.0, 2.5, "x"}.AsIntsSeq()))
+	equalSlice(t, []int64{1}, slices.Collect(A{1.0}.AsInt64sSeq()))
+	equalSlice(t, []float64{1.5}, slices.Collect(A{1.5, nil}.FloatsSeq()))
+	equalSlice(t, []bool{true, false}, slices.Collect(A{true, false}.BoolsSeq()))
+
+	var n int
+	for doc := range (A{M{"id": 1.0}, M{"id": 2.0}, "x", M{"id": 3.0}}).DocumentsSeq() {
+		n++
+		equal(t, n, doc.AsInt("id"))
+	}
+	equal(t, 2, n)
+}
@@ -0,0 +1,368 @@
+package typed
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Getter is the set of read accessors shared by M, A and *LazyM, so code can switch
+// between them.
+type Getter interface {
+	Exists(key string) bool
+	IsNumber(key string) bool
+	Bool(key string) bool
+	BoolOK(key string) (bool, bool)
+	AsInt(key string) int
+	AsIntOK(key string) (int, bool)
+	AsInt64(key string) int64
+	AsInt64OK(key string) (int64, bool)
+	Float(key string) float64
+	FloatOK(key string) (float64, bool)
+	StringValue(key string) string
+	StringValueOK(key string) (string, bool)
+	AsTime(key string) time.Time
+	AsTimeOK(key string) (time.Time, bool)
+	AsDuration(key string) time.Duration
+	AsDurationOK(key string) (time.Duration, bool)
+	Array(key string) A
+	ArrayOK(key string) (A, bool)
+	Document(key string) M
+	DocumentOK(key string) (M, bool)
+	RawMessage(key string) json.RawMessage
+}
+
+var (
+	_ Getter = M(nil)
+	_ Getter = A(nil)
+	_ Getter = (*LazyM)(nil)
+)
+
+// LazyM is a JSON document backed by its raw encoding. Unlike M, it is not parsed up
+// front: each accessor scans the raw encoding to the requested value only and parses
+// just that value, which is cached for later calls. This is cheaper than decoding an
+// M when only a few values of a large document are read.
+//
+// Syntax errors in the encoding are reported by the accessors scanning past them. A
+// LazyM is safe for concurrent use.
+type LazyM struct {
+	raw []byte
+
+	mu    sync.Mutex
+	cache map[string]any
+}
+
+// NewLazyM returns a LazyM backed by data, which must not be modified afterwards.
+func NewLazyM(data []byte) *LazyM {
+	return &LazyM{raw: data}
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. It stores a copy of data.
+func (l *LazyM) UnmarshalJSON(data []byte) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.raw = append([]byte(nil), data...)
+	l.cache = nil
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface. It returns the raw encoding.
+func (l *LazyM) MarshalJSON() ([]byte, error) {
+	if l == nil || l.raw == nil {
+		return []byte("null"), nil
+	}
+	return l.raw, nil
+}
+
+// find returns the raw encoding of the value for key.
+func (l *LazyM) find(key string) ([]byte, error) {
+	s := scanner{data: l.raw}
+	s.skipSpace()
+	if s.i < len(s.data) && s.data[s.i] != '{' {
+		return nil, fmt.Errorf("unknown type %s", kindOfByte(s.data[s.i]))
+	}
+	return findRaw(l.raw, key)
+}
+
+// value returns the parsed value for key.
+func (l *LazyM) value(key string) (any, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if v, ok := l.cache[key]; ok {
+		return v, nil
+	}
+	segs, err := segments(key)
+	if err != nil {
+		return nil, err
+	}
+
+	// A cached document or array holding the value saves scanning the raw encoding.
+	for n := len(segs) - 1; n > 0; n-- {
+		end := segs[n-1].end
+		if v, ok := l.cache[key[:end]]; ok {
+			switch v.(type) {
+			case M, A:
+				return lookupErr[any](v, subpath(key, end))
+			}
+		}
+	}
+
+	// Slices can't be found by scanning: parse the array holding the first one.
+	for n, seg := range segs {
+		if seg.slice && n > 0 {
+			v, err := l.parse(key[:segs[n-1].end])
+			if err != nil {
+				return nil, err
+			}
+			return lookupErr[any](v, subpath(key, segs[n-1].end))
+		}
+	}
+	return l.parse(key)
+}
+
+// parse parses and caches the value for key, found by scanning the raw encoding.
+func (l *LazyM) parse(key string) (any, error) {
+	raw, err := l.find(key)
+	if err != nil {
+		return nil, err
+	}
+	var p Parser
+	v, err := p.Parse(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	if l.cache == nil {
+		l.cache = make(map[string]any)
+	}
+	l.cache[key] = v
+	return v, nil
+}
+
+// subpath returns the path of the segments of path following offset end.
+func subpath(path Path, end int) Path {
+	if end < len(path) && path[end] == '.' {
+		end++
+	}
+	return path[end:]
+}
+
+func lazyLookupErr[E any](l *LazyM, key string) (e E, err error) {
+	v, err := l.value(key)
+	if err != nil {
+		return e, err
+	}
+	return convert[E](v)
+}
+
+func lazyLookup[E any](l *LazyM, key string) E {
+	e, err := lazyLookupErr[E](l, key)
+	if err != nil {
+		panic(err)
+	}
+	return e
+}
+
+func lazyLookupOK[E any](l *LazyM, key string) (E, bool) {
+	e, err := lazyLookupErr[E](l, key)
+	return e, err == nil
+}
+
+func kindOfByte(c byte) string {
+	switch c {
+	case '{':
+		return "object"
+	case '[':
+		return "array"
+	case '"':
+		return "string"
+	case 't', 'f':
+		return "bool"
+	case 'n':
+		return "null"
+	}
+	return "number"
+}
+
+// Exists reports whether key exists, potentially recursively for the given key. If
+// there are multiple keys concatenated with ".", this method will recurse down, as long as the
+// top and intermediate nodes are either documents or arrays. If an error
+// occurs or if the value doesn't exist, false is returned.
+func (l *LazyM) Exists(key string) bool {
+	_, ok := lazyLookupOK[any](l, key)
+	return ok
+}
+
+// IsNumber reports whether the value represents for given key is a JSON number.
+func (l *LazyM) IsNumber(key string) bool {
+	_, ok := lazyLookupOK[float64](l, key)
+	return ok
+}
+
+// Bool returns the boolean value the value represents for given key. It panics if the
+// value is a JSON type other than boolean.
+func (l *LazyM) Bool(key string) bool {
+	return lazyLookup[bool](l, key)
+}
+
+// BoolOK is the same as Bool, except it returns a boolean instead of
+// panicking.
+func (l *LazyM) BoolOK(key string) (bool, bool) {
+	return lazyLookupOK[bool](l, key)
+}
+
+// AsInt returns the int value the value represents for given key. It panics if the
+// value is JSON type other than number.
+func (l *LazyM) AsInt(key string) int {
+	return int(lazyLookup[float64](l, key))
+}
+
+// AsIntOK is the same as AsInt, except that it returns a boolean instead of
+// panicking.
+func (l *LazyM) AsIntOK(key string) (int, bool) {
+	f, ok := lazyLookupOK[float64](l, key)
+	return int(f), ok
+}
+
+// AsInt64 returns a JSON number as an int64 for given key. It panics if the
+// value type is JSON type other than number.
+func (l *LazyM) AsInt64(key string) int64 {
+	return int64(lazyLookup[float64](l, key))
+}
+
+// AsInt64OK is the same as AsInt64, except that it returns a boolean instead of
+// panicking.
+func (l *LazyM) AsInt64OK(key string) (int64, bool) {
+	f, ok := lazyLookupOK[float64](l, key)
+	return int64(f), ok
+}
+
+// Float returns the float64 value the value represents for given key. It panics if the
+// value is JSON type other than number.
+func (l *LazyM) Float(key string) float64 {
+	return lazyLookup[float64](l, key)
+}
+
+// FloatOK is the same as Float, but returns a boolean instead of panicking.
+func (l *LazyM) FloatOK(key string) (float64, bool) {
+	return lazyLookupOK[float64](l, key)
+}
+
+// StringValue returns the string value the value represents for given key. It panics if the
+// value is JSON type other than string.
+func (l *LazyM) StringValue(key string) string {
+	return lazyLookup[string](l, key)
+}
+
+// StringValueOK is the same as StringValue, but returns a boolean instead of
+// panicking.
+func (l *LazyM) StringValueOK(key string) (string, bool) {
+	return lazyLookupOK[string](l, key)
+}
+
+// AsTime returns the time.Time value the value represents for given key, an RFC 3339
+// string. It panics if the value can not be parsed; AsTimeWith accepts other formats.
+func (l *LazyM) AsTime(key string) time.Time {
+	t, err := parseTime(lazyLookupErr[any](l, key))
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// AsTimeOK is the same as AsTime, except it returns a boolean instead of
+// panicking.
+func (l *LazyM) AsTimeOK(key string) (time.Time, bool) {
+	t, err := parseTime(lazyLookupErr[any](l, key))
+	return t, err == nil
+}
+
+// AsTimeWith returns the time.Time value the value represents for given key as
+// parsed by p, and the layout that matched it.
+func (l *LazyM) AsTimeWith(key string, p *TimeParser) (time.Time, string, error) {
+	return p.parse(lazyLookupErr[any](l, key))
+}
+
+// AsDuration returns the time.Duration value the value represents for given key, a string
+// ParseDuration accepts or a number of seconds. It panics if the value can not be parsed.
+func (l *LazyM) AsDuration(key string) time.Duration {
+	d, err := parseDuration(lazyLookupErr[any](l, key))
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// AsDurationOK is the same as AsDuration, except it returns a boolean instead of
+// panicking.
+func (l *LazyM) AsDurationOK(key string) (time.Duration, bool) {
+	d, err := parseDuration(lazyLookupErr[any](l, key))
+	return d, err == nil
+}
+
+// AsDurationWith returns the time.Duration value the value represents for given key as
+// parsed by p.
+func (l *LazyM) AsDurationWith(key string, p *DurationParser) (time.Duration, error) {
+	return p.parse(lazyLookupErr[any](l, key))
+}
+
+// Array returns the JSON array the value represents for given key. It panics if the
+// value is a JSON type other than array.
+func (l *LazyM) Array(key string) A {
+	return lazyLookup[A](l, key)
+}
+
+// ArrayOK is the same as Array, except it returns a boolean instead of
+// panicking.
+func (l *LazyM) ArrayOK(key string) (A, bool) {
+	return lazyLookupOK[A](l, key)
+}
+
+// Document returns the JSON document the value represents for given key. It panics if the
+// value is a JSON type other than document.
+func (l *LazyM) Document(key string) M {
+	return lazyLookup[M](l, key)
+}
+
+// DocumentOK is the same as Document, except it returns a boolean instead of
+// panicking.
+func (l *LazyM) DocumentOK(key string) (M, bool) {
+	return lazyLookupOK[M](l, key)
+}
+
+// RawMessage returns the raw encoded JSON value the value represents for given key, as found
+// in the backing encoding. It returns 'null' if the value doesn't exist.
+func (l *LazyM) RawMessage(key string) json.RawMessage {
+	raw, err := l.find(key)
+	if err == nil {
+		return json.RawMessage(raw)
+	}
+
+	// Slices are not found in the raw encoding.
+	v, err := l.value(key)
+	if err != nil {
+		return nullRawMessage
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	return json.RawMessage(b)
+}
+
+// Any search the document, potentially recursively, for the given key. If
+// there are multiple keys concatenated with ".", this method will recurse down, as long as the
+// top and intermediate nodes are either documents or arrays. If an error
+// occurs or if the value doesn't exist, this method panics.
+func (l *LazyM) Any(key string) any {
+	return lazyLookup[any](l, key)
+}
+
+// AnyOK is the same as Any, except it returns a boolean instead of
+// panicking.
+func (l *LazyM) AnyOK(key string) (any, bool) {
+	return lazyLookupOK[any](l, key)
+}
@@ -0,0 +1,162 @@
+package typed
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestLazyM(t *testing.T) {
+	t.Parallel()
+
+	var j = []byte(`{
+		"Name": "Wednesday",
+		"Age": 6,
+		"Profile": {"Zodiac": "Scorpio", "Born": "2004-10-31T00:00:00Z", "Nap": "1h30m"},
+		"Parents": ["Gomez", {"Name": "Morticia", "Age": 40}],
+		"Pet": null,
+		"Name": "Wednesday Addams"
+	}`)
+
+	l := NewLazyM(j)
+	equal(t, "Wednesday Addams", l.StringValue("Name"))
+	equal(t, 6, l.AsInt("Age"))
+	equal(t, int64(6), l.AsInt64("Age"))
+	equal(t, true, l.IsNumber("Age"))
+	equal(t, false, l.IsNumber("Name"))
+	equal(t, 2004, l.AsTime("Profile.Born").Year())
+	equal(t, "1h30m0s", l.AsDuration("Profile.Nap").String())
+	equal(t, "Morticia", l.StringValue("Parents.1.Name"))
+	equal(t, 40, l.AsInt("Parents.1.Age"))
+	equal(t, false, l.Exists("Pet"))
+	equal(t, false, l.Exists("Parents.2"))
+	equal(t, false, l.Exists("Name.First"))
+	equal(t, "Scorpio", l.Document("Profile").StringValue("Zodiac"))
+	equal(t, 2, len(l.Array("Parents")))
+	equal(t, `{"Name": "Morticia", "Age": 40}`, string(l.RawMessage("Parents.1")))
+	equal(t, "null", string(l.RawMessage("Missing")))
+
+	_, ok := l.StringValueOK("Age")
+	equal(t, false, ok)
+	equal(t, true, panics(func() { l.Bool("Missing") }))
+
+	b, err := json.Marshal(l)
+	if err != nil {
+		t.Fatal(err)
+	}
+	equal(t, true, json.Valid(b))
+}
+
+func TestLazyM_Getter(t *testing.T) {
+	t.Parallel()
+
+	var j = []byte(`{"Name": "Wednesday", "Parents": [{"Name": "Gomez"}], "Profile": {"Age": 6}}`)
+
+	var m M
+	if err := json.Unmarshal(j, &m); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, g := range []Getter{m, NewLazyM(j)} {
+		equal(t, "Wednesday", g.StringValue("Name"))
+		equal(t, "Gomez", g.StringValue("Parents.0.Name"))
+		equal(t, 6, g.AsInt("Profile.Age"))
+		equal(t, 6, g.Document("Profile").AsInt("Age"))
+		equal(t, false, g.Exists("Profile.Name"))
+	}
+}
+
+func TestLazyM_Null(t *testing.T) {
+	t.Parallel()
+
+	var j = []byte(`{"Pet": null, "Parents": [null, {"Name": null}]}`)
+
+	var m M
+	if err := json.Unmarshal(j, &m); err != nil {
+		t.Fatal(err)
+	}
+	l := NewLazyM(j)
+
+	for _, key := range []string{"Pet", "Parents.0", "Parents.1.Name"} {
+		equal(t, m.Exists(key), l.Exists(key))
+		equal(t, false, l.Exists(key))
+
+		_, mok := m.AnyOK(key)
+		_, lok := l.AnyOK(key)
+		equal(t, mok, lok)
+		equal(t, false, lok)
+
+		equal(t, panics(func() { m.Any(key) }), panics(func() { l.Any(key) }))
+	}
+}
+
+func TestLazyM_Cache(t *testing.T) {
+	t.Parallel()
+
+	l := NewLazyM([]byte(`{"Profile": {"Zodiac": "Scorpio"}}`))
+	l.Document("Profile")
+
+	// Values within the cached document are read without scanning the encoding.
+	l.raw = nil
+	equal(t, "Scorpio", l.StringValue("Profile.Zodiac"))
+}
+
+func TestLazyM_Unmarshal(t *testing.T) {
+	t.Parallel()
+
+	var v struct {
+		Doc *LazyM
+	}
+	data := []byte(`{"Doc": {"Name": "Wednesday"}}`)
+	if err := json.Unmarshal(data, &v); err != nil {
+		t.Fatal(err)
+	}
+	data[10] = 'X'
+	equal(t, "Wednesday", v.Doc.StringValue("Name"))
+}
+
+func TestLazyM_NotDocument(t *testing.T) {
+	t.Parallel()
+
+	l := NewLazyM([]byte(`["Gomez"]`))
+	equal(t, false, l.Exists("0"))
+
+	l = NewLazyM([]byte(`{"Name": "Wednesday", "Age": tru}`))
+	_, ok := l.StringValueOK("Name")
+	equal(t, false, ok)
+}
+
+func BenchmarkLazyM(b *testing.B) {
+	data := []byte(`{"events": ` + string(benchmarkEvents(1000)) + `, "id": "batch-1"}`)
+
+	b.Run("LazyM", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			l := NewLazyM(data)
+			_ = l.StringValue("id")
+			_ = l.AsInt("events.10.id")
+		}
+	})
+
+	b.Run("M", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var m M
+			if err := m.UnmarshalJSON(data); err != nil {
+				b.Fatal(err)
+			}
+			_ = m.StringValue("id")
+			_ = m.AsInt("events.10.id")
+		}
+	})
+}
+
+func TestLazyM_PathGrammar(t *testing.T) {
+	t.Parallel()
+
+	l := NewLazyM([]byte(`{"labels": {"app.kubernetes.io/name": "web"}, "items": [{"n": 1}, {"n": 2}, {"n": 3}]}`))
+	equal(t, "web", l.StringValue(`labels["app.kubernetes.io/name"]`))
+	equal(t, 3, l.AsInt("items.-1.n"))
+	equal(t, 2, len(l.Array("items.1:3")))
+	equal(t, 3, l.AsInt("items.1:3.1.n"))
+	equal(t, `[{"n":2},{"n":3}]`, string(l.RawMessage("items.1:")))
+}
@@ -0,0 +1,34 @@
+package typed
+
+// Lenient returns the elements of a which are of type T, converting numbers to the
+// integer and floating-point types T as Slices does, and the indexes of the elements
+// skipped as they are of another type, nulls included.
+func Lenient[T any](a A) ([]T, []int) {
+	s := make([]T, 0, len(a))
+	var skipped []int
+	for i, v := range a {
+		e, ok := element[T](v)
+		if !ok {
+			skipped = append(skipped, i)
+			continue
+		}
+		s = append(s, e)
+	}
+	return s, skipped
+}
+
+// LenientOr is the same as Lenient, except the elements of another type are replaced
+// with def instead of being skipped, so the slice returned is as long as a.
+func LenientOr[T any](a A, def T) ([]T, []int) {
+	s := make([]T, len(a))
+	var replaced []int
+	for i, v := range a {
+		e, ok := element[T](v)
+		if !ok {
+			replaced = append(replaced, i)
+			e = def
+		}
+		s[i] = e
+	}
+	return s, replaced
+}
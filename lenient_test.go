@@ -0,0 +1,43 @@
+package typed
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestLenient(t *testing.T) {
+	t.Parallel()
+
+	var a A
+	if err := json.Unmarshal([]byte(`["a", null, "b", 3, {"c": 1}, "d"]`), &a); err != nil {
+		t.Fatal(err)
+	}
+
+	s, skipped := Lenient[string](a)
+	equalSlice(t, []string{"a", "b", "d"}, s)
+	equalSlice(t, []int{1, 3, 4}, skipped)
+
+	s, replaced := LenientOr(a, "?")
+	equalSlice(t, []string{"a", "?", "b", "?", "?", "d"}, s)
+	equalSlice(t, []int{1, 3, 4}, replaced)
+
+	n, skipped := Lenient[int64](a)
+	equalSlice(t, []int64{3}, n)
+	equal(t, 5, len(skipped))
+
+	u, skipped := Lenient[uint](A{1.0, -2.0, json.Number("3"), "4"})
+	equalSlice(t, []uint{1, 3}, u)
+	equalSlice(t, []int{1, 3}, skipped)
+
+	docs, _ := Lenient[M](a)
+	equal(t, 1, len(docs))
+	equal(t, 1, docs[0].AsInt("c"))
+
+	s, skipped = Lenient[string](A{"x", "y"})
+	equalSlice(t, []string{"x", "y"}, s)
+	equal(t, 0, len(skipped))
+
+	b, replaced := LenientOr(A{true, "yes", false}, false)
+	equalSlice(t, []bool{true, false, false}, b)
+	equalSlice(t, []int{1}, replaced)
+}
@@ -0,0 +1,134 @@
+package typed
+
+import (
+	"fmt"
+
+	"golang.org/x/exp/constraints"
+)
+
+// StringMap returns the map[string]string the document represents for given key. It
+// panics if the value is not a document, or if one of its values is a JSON type other
+// than string.
+func (m M) StringMap(key string) map[string]string {
+	return must(typedMap[string](m, key, "string"))
+}
+
+// StringMapOK is the same as StringMap, except it returns a boolean instead of
+// panicking.
+func (m M) StringMapOK(key string) (map[string]string, bool) {
+	v, err := typedMap[string](m, key, "string")
+	return v, err == nil
+}
+
+// StringMapErr is the same as StringMap, except it returns an error, reporting the path
+// of the offending value, instead of panicking.
+func (m M) StringMapErr(key string) (map[string]string, error) {
+	return typedMap[string](m, key, "string")
+}
+
+// IntMap returns the map[string]int64 the document represents for given key. It panics
+// if the value is not a document, or if one of its values is a JSON type other than
+// number.
+func (m M) IntMap(key string) map[string]int64 {
+	return must(numericMap[int64](m, key, "number"))
+}
+
+// IntMapOK is the same as IntMap, except it returns a boolean instead of panicking.
+func (m M) IntMapOK(key string) (map[string]int64, bool) {
+	v, err := numericMap[int64](m, key, "number")
+	return v, err == nil
+}
+
+// IntMapErr is the same as IntMap, except it returns an error, reporting the path of
+// the offending value, instead of panicking.
+func (m M) IntMapErr(key string) (map[string]int64, error) {
+	return numericMap[int64](m, key, "number")
+}
+
+// FloatMap returns the map[string]float64 the document represents for given key. It
+// panics if the value is not a document, or if one of its values is a JSON type other
+// than number.
+func (m M) FloatMap(key string) map[string]float64 {
+	return must(numericMap[float64](m, key, "number"))
+}
+
+// FloatMapOK is the same as FloatMap, except it returns a boolean instead of panicking.
+func (m M) FloatMapOK(key string) (map[string]float64, bool) {
+	v, err := numericMap[float64](m, key, "number")
+	return v, err == nil
+}
+
+// FloatMapErr is the same as FloatMap, except it returns an error, reporting the path
+// of the offending value, instead of panicking.
+func (m M) FloatMapErr(key string) (map[string]float64, error) {
+	return numericMap[float64](m, key, "number")
+}
+
+// BoolMap returns the map[string]bool the document represents for given key. It panics
+// if the value is not a document, or if one of its values is a JSON type other than
+// boolean.
+func (m M) BoolMap(key string) map[string]bool {
+	return must(typedMap[bool](m, key, "boolean"))
+}
+
+// BoolMapOK is the same as BoolMap, except it returns a boolean instead of panicking.
+func (m M) BoolMapOK(key string) (map[string]bool, bool) {
+	v, err := typedMap[bool](m, key, "boolean")
+	return v, err == nil
+}
+
+// BoolMapErr is the same as BoolMap, except it returns an error, reporting the path of
+// the offending value, instead of panicking.
+func (m M) BoolMapErr(key string) (map[string]bool, error) {
+	return typedMap[bool](m, key, "boolean")
+}
+
+// DocumentMap returns the map[string]M the document represents for given key. It panics
+// if the value is not a document, or if one of its values is a JSON type other than
+// document.
+func (m M) DocumentMap(key string) map[string]M {
+	return must(typedMap[M](m, key, "document"))
+}
+
+// DocumentMapOK is the same as DocumentMap, except it returns a boolean instead of
+// panicking.
+func (m M) DocumentMapOK(key string) (map[string]M, bool) {
+	v, err := typedMap[M](m, key, "document")
+	return v, err == nil
+}
+
+// DocumentMapErr is the same as DocumentMap, except it returns an error, reporting the
+// path of the offending value, instead of panicking.
+func (m M) DocumentMapErr(key string) (map[string]M, error) {
+	return typedMap[M](m, key, "document")
+}
+
+func typedMap[E any](m M, key, kind string) (map[string]E, error) {
+	return convertMap(m, key, kind, as[E])
+}
+
+func numericMap[E constraints.Integer | constraints.Float](m M, key, kind string) (map[string]E, error) {
+	return convertMap(m, key, kind, func(v any) (E, bool) {
+		f, ok := toFloat(v)
+		return E(f), ok
+	})
+}
+
+// convertMap returns the values of the document for given key within m converted by
+// conv, or an error naming the first value in sorted key order conv rejects.
+func convertMap[E any](m M, key, kind string, conv func(any) (E, bool)) (map[string]E, error) {
+	doc, err := lookupErr[M](m, key)
+	if err != nil {
+		return nil, err
+	}
+
+	s := make(map[string]E, len(doc))
+	for k, v := range doc.All() {
+		e, ok := conv(v)
+		if !ok {
+			return nil, fmt.Errorf("value for key %q of type %T is not a %s", childPath(key, EscapeKey(k)), v, kind)
+		}
+		s[k] = e
+	}
+	return s, nil
+}
@@ -0,0 +1,51 @@
+package typed
+
+import (
+	"encoding/json"
+	"maps"
+	"testing"
+)
+
+func TestTypedMaps(t *testing.T) {
+	t.Parallel()
+
+	var m M
+	err := json.Unmarshal([]byte(`{
+		"labels": {"app": "web", "tier": "front"},
+		"weights": {"a": 1, "b": 2.5},
+		"flags": {"beta": true, "dark.mode": false},
+		"services": {"api": {"port": 8080}, "db": {"port": 5432}},
+		"mixed": {"a": "x", "b": 1, "c": null},
+		"empty": {}
+	}`), &m)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	equal(t, true, maps.Equal(map[string]string{"app": "web", "tier": "front"}, m.StringMap("labels")))
+	equal(t, true, maps.Equal(map[string]int64{"a": 1, "b": 2}, m.IntMap("weights")))
+	equal(t, true, maps.Equal(map[string]float64{"a": 1, "b": 2.5}, m.FloatMap("weights")))
+	equal(t, true, maps.Equal(map[string]bool{"beta": true, "dark.mode": false}, m.BoolMap("flags")))
+	services := m.DocumentMap("services")
+	equal(t, 5432, services["db"].AsInt("port"))
+	equal(t, 0, len(m.StringMap("empty")))
+
+	_, err = m.StringMapErr("mixed")
+	equal(t, `value for key "mixed.b" of type float64 is not a string`, err.Error())
+	_, err = m.BoolMapErr("flags.beta")
+	equal(t, true, err != nil)
+	_, err = m.FloatMapErr("missing")
+	equal(t, `not found key "missing"`, err.Error())
+	_, err = m.IntMapErr("labels")
+	equal(t, `value for key "labels.app" of type string is not a number`, err.Error())
+
+	_, ok := m.DocumentMapOK("labels")
+	equal(t, false, ok)
+	_, ok = m.StringMapOK("labels")
+	equal(t, true, ok)
+	equal(t, true, panics(func() { m.BoolMap("weights") }))
+
+	num := M{"n": M{"x": json.Number("3")}}
+	equal(t, 3.0, num.FloatMap("n")["x"])
+	equal(t, int64(3), num.IntMap("n")["x"])
+}
@@ -0,0 +1,129 @@
+package typed
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+)
+
+// Arrays returns the slice of JSON array the array represents. It panics if one of
+// elements is a JSON type other than array.
+func (a A) Arrays() []A {
+	return must(arrays(a))
+}
+
+// ArraysOK is the same as Arrays, except it returns a boolean instead of panicking.
+func (a A) ArraysOK() ([]A, bool) {
+	s, err := arrays(a)
+	return s, err == nil
+}
+
+// Matrix returns the rows of numbers the array represents, such as [[1, 2], [3, 4]].
+// It panics if one of elements is not an array of numbers, or if the rows are of
+// different lengths.
+func (a A) Matrix() [][]float64 {
+	return must(RectSlices[float64](a))
+}
+
+// MatrixOK is the same as Matrix, except it returns a boolean instead of panicking.
+func (a A) MatrixOK() ([][]float64, bool) {
+	s, err := RectSlices[float64](a)
+	return s, err == nil
+}
+
+// Slices returns the slices of T the arrays within a represent, which may be of
+// different lengths. Numbers convert to the integer and floating-point types T. The
+// error reports the index of the first element of another type, such as "1.2".
+func Slices[T any](a A) ([][]T, error) {
+	rows, err := arrays(a)
+	if err != nil {
+		return nil, err
+	}
+
+	s := make([][]T, len(rows))
+	for i, row := range rows {
+		s[i] = make([]T, len(row))
+		for j, v := range row {
+			e, ok := element[T](v)
+			if !ok {
+				return nil, fmt.Errorf("element %d.%d of type %T is not %s", i, j, v, reflect.TypeFor[T]())
+			}
+			s[i][j] = e
+		}
+	}
+	return s, nil
+}
+
+// RectSlices is the same as Slices, except it returns an error if the arrays are of
+// different lengths.
+func RectSlices[T any](a A) ([][]T, error) {
+	s, err := Slices[T](a)
+	if err != nil {
+		return nil, err
+	}
+	for i, row := range s {
+		if len(row) != len(s[0]) {
+			return nil, fmt.Errorf("element %d has %d elements, want %d as element 0", i, len(row), len(s[0]))
+		}
+	}
+	return s, nil
+}
+
+func arrays(a A) ([]A, error) {
+	if a == nil {
+		return nil, nil
+	}
+
+	s := make([]A, len(a))
+	for i, v := range a {
+		switch x := v.(type) {
+		case A:
+			s[i] = x
+		case []any:
+			s[i] = x
+		default:
+			return nil, fmt.Errorf("element %d of type %T is not an array", i, v)
+		}
+	}
+	return s, nil
+}
+
+// element returns v as a T, converting numbers to the integer and floating-point
+// types, and accepting nulls for interface types. Numbers out of the range of T, and
+// negative numbers for unsigned T, aren't converted.
+func element[T any](v any) (T, bool) {
+	if e, ok := as[T](v); ok {
+		return e, true
+	}
+
+	var e T
+	if v == nil && any(e) == nil {
+		// A null is held by interface types only.
+		return e, true
+	}
+	f, ok := toFloat(v)
+	if !ok {
+		return e, false
+	}
+	r := reflect.ValueOf(&e).Elem()
+	switch r.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if f < math.MinInt64 || f >= math.MaxInt64 || r.OverflowInt(int64(f)) {
+			return e, false
+		}
+		r.SetInt(int64(f))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if f < 0 || f >= math.MaxUint64 || r.OverflowUint(uint64(f)) {
+			return e, false
+		}
+		r.SetUint(uint64(f))
+	case reflect.Float32, reflect.Float64:
+		if r.OverflowFloat(f) {
+			return e, false
+		}
+		r.SetFloat(f)
+	default:
+		return e, false
+	}
+	return e, true
+}
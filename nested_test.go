@@ -0,0 +1,73 @@
+package typed
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestA_Nested(t *testing.T) {
+	t.Parallel()
+
+	var m M
+	err := json.Unmarshal([]byte(`{
+		"coordinates": [[102.0, 0.5], [103.0, 1.0], [104.0, 0.0]],
+		"ragged": [[1], [2, 3], []],
+		"rows": [["a", "b"], ["c", "d"]],
+		"mixed": [[1, 2], [3, "x"]],
+		"flat": [1, 2]
+	}`), &m)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	coords := m.Array("coordinates").Matrix()
+	equal(t, 3, len(coords))
+	equalSlice(t, []float64{103, 1}, coords[1])
+
+	arrays := m.Array("ragged").Arrays()
+	equal(t, 3, len(arrays))
+	equal(t, 2, len(arrays[1]))
+	_, ok := m.Array("flat").ArraysOK()
+	equal(t, false, ok)
+
+	ragged, err := Slices[int](m.Array("ragged"))
+	equal(t, nil, err)
+	equalSlice(t, []int{2, 3}, ragged[1])
+	equal(t, 0, len(ragged[2]))
+
+	_, err = RectSlices[int](m.Array("ragged"))
+	equal(t, "element 1 has 2 elements, want 1 as element 0", err.Error())
+	_, ok = m.Array("ragged").MatrixOK()
+	equal(t, false, ok)
+
+	rows, err := RectSlices[string](m.Array("rows"))
+	equal(t, nil, err)
+	equal(t, "d", rows[1][1])
+
+	_, err = Slices[float64](m.Array("mixed"))
+	equal(t, "element 1.1 of type string is not float64", err.Error())
+	_, err = Slices[string](m.Array("flat"))
+	equal(t, "element 0 of type float64 is not an array", err.Error())
+	equal(t, true, panics(func() { m.Array("mixed").Matrix() }))
+
+	vectors, err := Slices[float32](A{A{json.Number("0.5"), 1.0}})
+	equal(t, nil, err)
+	equalSlice(t, []float32{0.5, 1}, vectors[0])
+	anys, err := Slices[any](A{A{nil, "x"}})
+	equal(t, nil, err)
+	equal(t, nil, anys[0][0])
+
+	bytes, err := Slices[uint8](A{A{0.0, 255.0}, A{json.Number("7")}})
+	equal(t, nil, err)
+	equalSlice(t, []uint8{0, 255}, bytes[0])
+	equalSlice(t, []uint8{7}, bytes[1])
+	_, err = Slices[uint8](A{A{256.0}})
+	equal(t, "element 0.0 of type float64 is not uint8", err.Error())
+	_, err = Slices[uint16](A{A{-1.0}})
+	equal(t, "element 0.0 of type float64 is not uint16", err.Error())
+
+	type celsius float64
+	temps, err := Slices[celsius](A{A{21.5}})
+	equal(t, nil, err)
+	equal(t, celsius(21.5), temps[0][0])
+}
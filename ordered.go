@@ -0,0 +1,384 @@
+package typed
+
+import (
+	"bytes"
+	"encoding/json"
+	"maps"
+	"reflect"
+	"slices"
+	"time"
+)
+
+// An E is an element of a D.
+type E struct {
+	Key   string
+	Value any
+}
+
+// D is an ordered representation of a JSON document. Unlike M, it records the
+// order of keys in the source, iterates in that order and marshals in that order,
+// so re-encoding a decoded D reproduces the original key order.
+//
+// Nested documents decoded into a D are D values themselves, and nested arrays are A
+// values. If a key occurs more than once, the last value wins and keeps the position
+// of the first occurrence.
+//
+// Looking up a key scans the elements of the D, so lookups take time linear in its
+// length; use M for large documents accessed by key.
+type D []E
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (d *D) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(null, data) {
+		return nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok != json.Delim('{') {
+		return &json.UnmarshalTypeError{Value: kindOf(tok), Type: reflect.TypeOf(*d), Offset: dec.InputOffset()}
+	}
+
+	v, err := decodeOrdered(dec, tok)
+	if err != nil {
+		return err
+	}
+	*d = v.(D)
+	return nil
+}
+
+// decodeOrdered decodes the value starting with tok, objects as D and arrays as A.
+func decodeOrdered(dec *json.Decoder, tok json.Token) (any, error) {
+	switch tok {
+	default:
+		return tok, nil
+	case json.Delim('{'):
+		d := D{}
+		seen := make(map[string]int)
+		for dec.More() {
+			tok, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			key := tok.(string)
+
+			if tok, err = dec.Token(); err != nil {
+				return nil, err
+			}
+			v, err := decodeOrdered(dec, tok)
+			if err != nil {
+				return nil, err
+			}
+
+			if j, ok := seen[key]; ok {
+				d[j].Value = v
+			} else {
+				seen[key] = len(d)
+				d = append(d, E{key, v})
+			}
+		}
+		_, err := dec.Token()
+		return d, err
+	case json.Delim('['):
+		a := A{}
+		for dec.More() {
+			tok, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			v, err := decodeOrdered(dec, tok)
+			if err != nil {
+				return nil, err
+			}
+			a = append(a, v)
+		}
+		_, err := dec.Token()
+		return a, err
+	}
+}
+
+// MarshalJSON implements the json.Marshaler interface. Keys are written in the
+// order of the document.
+func (d D) MarshalJSON() ([]byte, error) {
+	if d == nil {
+		return []byte("null"), nil
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, e := range d {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		k, err := json.Marshal(e.Key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(k)
+		buf.WriteByte(':')
+
+		v, err := json.Marshal(e.Value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func (d D) index(key string) int {
+	for i, e := range d {
+		if e.Key == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// Exists reports whether key exists, potentially recursively for the given key. If
+// there are multiple keys concatenated with ".", this method will recurse down, as long as the
+// top and intermediate nodes are either documents or arrays. If an error
+// occurs or if the value doesn't exist, false is returned.
+func (d D) Exists(key string) bool {
+	_, ok := lookupOK[any](d, key)
+	return ok
+}
+
+// IsNumber reports whether the value represents for given key is a JSON number.
+func (d D) IsNumber(key string) bool {
+	_, ok := lookupOK[float64](d, key)
+	return ok
+}
+
+// Bool returns the boolean value the value represents for given key. It panics if the
+// value is a JSON type other than boolean.
+func (d D) Bool(key string) bool {
+	return lookup[bool](d, key)
+}
+
+// BoolOK is the same as Bool, except it returns a boolean instead of
+// panicking.
+func (d D) BoolOK(key string) (bool, bool) {
+	return lookupOK[bool](d, key)
+}
+
+// AsInt returns the int value the value represents for given key. It panics if the
+// value is JSON type other than number.
+func (d D) AsInt(key string) int {
+	return int(lookup[float64](d, key))
+}
+
+// AsIntOK is the same as AsInt, except that it returns a boolean instead of
+// panicking.
+func (d D) AsIntOK(key string) (int, bool) {
+	f, ok := lookupOK[float64](d, key)
+	return int(f), ok
+}
+
+// AsInt64 returns a JSON number as an int64 for given key. It panics if the
+// value type is JSON type other than number.
+func (d D) AsInt64(key string) int64 {
+	return int64(lookup[float64](d, key))
+}
+
+// AsInt64OK is the same as AsInt64, except that it returns a boolean instead of
+// panicking.
+func (d D) AsInt64OK(key string) (int64, bool) {
+	f, ok := lookupOK[float64](d, key)
+	return int64(f), ok
+}
+
+// Float returns the float64 value the value represents for given key. It panics if the
+// value is JSON type other than number.
+func (d D) Float(key string) float64 {
+	return lookup[float64](d, key)
+}
+
+// FloatOK is the same as Float, but returns a boolean instead of panicking.
+func (d D) FloatOK(key string) (float64, bool) {
+	return lookupOK[float64](d, key)
+}
+
+// StringValue returns the string value the value represents for given key. It panics if the
+// value is JSON type other than string.
+func (d D) StringValue(key string) string {
+	return lookup[string](d, key)
+}
+
+// StringValueOK is the same as StringValue, but returns a boolean instead of
+// panicking.
+func (d D) StringValueOK(key string) (string, bool) {
+	return lookupOK[string](d, key)
+}
+
+// AsTime returns the time.Time value the value represents for given key, an RFC 3339
+// string. It panics if the value can not be parsed; AsTimeWith accepts other formats.
+func (d D) AsTime(key string) time.Time {
+	t, err := asTimeErr(d, key)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// AsTimeOK is the same as AsTime, except it returns a boolean instead of
+// panicking.
+func (d D) AsTimeOK(key string) (time.Time, bool) {
+	t, err := asTimeErr(d, key)
+	return t, err == nil
+}
+
+// AsTimeWith returns the time.Time value the value represents for given key as
+// parsed by p, and the layout that matched it.
+func (d D) AsTimeWith(key string, p *TimeParser) (time.Time, string, error) {
+	return p.parse(lookupErr[any](d, key))
+}
+
+// AsDuration returns the time.Duration value the value represents for given key, a string
+// ParseDuration accepts or a number of seconds. It panics if the value can not be parsed.
+func (d D) AsDuration(key string) time.Duration {
+	v, err := asDurationErr(d, key)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// AsDurationOK is the same as AsDuration, except it returns a boolean instead of
+// panicking.
+func (d D) AsDurationOK(key string) (time.Duration, bool) {
+	v, err := asDurationErr(d, key)
+	return v, err == nil
+}
+
+// AsDurationWith returns the time.Duration value the value represents for given key as
+// parsed by p.
+func (d D) AsDurationWith(key string, p *DurationParser) (time.Duration, error) {
+	return p.parse(lookupErr[any](d, key))
+}
+
+// Array returns the JSON array the value represents for given key. It panics if the
+// value is a JSON type other than array.
+func (d D) Array(key string) A {
+	return lookup[A](d, key)
+}
+
+// ArrayOK is the same as Array, except it returns a boolean instead of
+// panicking.
+func (d D) ArrayOK(key string) (A, bool) {
+	return lookupOK[A](d, key)
+}
+
+// Document returns the ordered JSON document the value represents for given key. It panics if the
+// value is a JSON type other than document.
+func (d D) Document(key string) D {
+	return lookup[D](d, key)
+}
+
+// DocumentOK is the same as Document, except it returns a boolean instead of
+// panicking.
+func (d D) DocumentOK(key string) (D, bool) {
+	return lookupOK[D](d, key)
+}
+
+// RawMessage returns the raw encoded JSON value the value represents for given key. It returns 'null' if the
+// value doesn't exist.
+func (d D) RawMessage(key string) json.RawMessage {
+	v, ok := lookupOK[any](d, key)
+	if !ok {
+		return nullRawMessage
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	return json.RawMessage(b)
+}
+
+// Any search the document, potentially recursively, for the given key. If
+// there are multiple keys concatenated with ".", this method will recurse down, as long as the
+// top and intermediate nodes are either documents or arrays. If an error
+// occurs or if the value doesn't exist, this method panics.
+func (d D) Any(key string) any {
+	return lookup[any](d, key)
+}
+
+// AnyOK is the same as Any, except it returns a boolean instead of
+// panicking.
+func (d D) AnyOK(key string) (any, bool) {
+	return lookupOK[any](d, key)
+}
+
+// Set sets the value for given key, creating intermediate documents as needed. A new
+// key is added after the existing keys of its document. Maps within value, including
+// M, are converted to D with their keys sorted, and slices to A, so that the accessors
+// of D reach into them. It panics if an intermediate node is a JSON type other than
+// document or array, or if an array index is out of range.
+func (d *D) Set(key string, value any) {
+	v, err := setErr(*d, key, orderedWrapper(value))
+	if err != nil {
+		panic(err)
+	}
+	*d = v.(D)
+}
+
+// SetOK is the same as Set, except it returns a boolean instead of
+// panicking.
+func (d *D) SetOK(key string, value any) bool {
+	v, err := setErr(*d, key, orderedWrapper(value))
+	if err != nil {
+		return false
+	}
+	*d = v.(D)
+	return true
+}
+
+// orderedWrapper is the same as wrapper, except it converts maps to D, sorted by key.
+func orderedWrapper(a any) any {
+	switch x := a.(type) {
+	default:
+		return a
+	case map[string]any:
+		return orderedWrapper(M(x))
+	case M:
+		d := make(D, 0, len(x))
+		for _, k := range slices.Sorted(maps.Keys(x)) {
+			d = append(d, E{k, orderedWrapper(x[k])})
+		}
+		return d
+	case []any:
+		return orderedWrapper(A(x))
+	case A:
+		for i, v := range x {
+			x[i] = orderedWrapper(v)
+		}
+		return x
+	}
+}
+
+// Delete removes the value for given key, potentially recursively, keeping the order of
+// the remaining keys. If the value doesn't exist or key is malformed, Delete is a no-op.
+func (d *D) Delete(key string) {
+	if segs, err := segments(key); err == nil {
+		*d = deleteKeys(*d, segs).(D)
+	}
+}
+
+// Keys returns all keys within document, in document order.
+func (d D) Keys() []string {
+	if d == nil {
+		return nil
+	}
+
+	keys := make([]string, len(d))
+	for i, e := range d {
+		keys[i] = e.Key
+	}
+	return keys
+}
@@ -0,0 +1,105 @@
+package typed
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestD(t *testing.T) {
+	t.Parallel()
+
+	var j = []byte(`{
+		"Name": "Wednesday",
+		"Age": 6,
+		"Profile": {"Zodiac": "Scorpio", "Born": "2004-10-31T00:00:00Z"},
+		"Parents": ["Gomez", {"Name": "Morticia", "Age": 40}]
+	}`)
+
+	var d D
+	err := json.Unmarshal(j, &d)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	equalSlice(t, []string{"Name", "Age", "Profile", "Parents"}, d.Keys())
+	equalSlice(t, []string{"Zodiac", "Born"}, d.Document("Profile").Keys())
+	equal(t, "Wednesday", d.StringValue("Name"))
+	equal(t, 6, d.AsInt("Age"))
+	equal(t, 2004, d.AsTime("Profile.Born").Year())
+	equal(t, "Morticia", d.StringValue("Parents.1.Name"))
+	equal(t, false, d.Exists("Parents.2"))
+
+	b, err := json.Marshal(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	equal(t, `{"Name":"Wednesday","Age":6,"Profile":{"Zodiac":"Scorpio","Born":"2004-10-31T00:00:00Z"},"Parents":["Gomez",{"Name":"Morticia","Age":40}]}`, string(b))
+}
+
+func TestD_DuplicateKeys(t *testing.T) {
+	t.Parallel()
+
+	var d D
+	err := json.Unmarshal([]byte(`{"b": 1, "a": 2, "b": 3}`), &d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	equalSlice(t, []string{"b", "a"}, d.Keys())
+	equal(t, 3, d.AsInt("b"))
+}
+
+func TestD_NotDocument(t *testing.T) {
+	t.Parallel()
+
+	var d D
+	err := json.Unmarshal([]byte(`["Gomez"]`), &d)
+	if _, ok := err.(*json.UnmarshalTypeError); !ok {
+		t.Errorf("want *json.UnmarshalTypeError; got %v", err)
+	}
+}
+
+func TestD_SetDelete(t *testing.T) {
+	t.Parallel()
+
+	var d D
+	err := json.Unmarshal([]byte(`{"Name": "Wednesday", "Parents": ["Gomez", "Morticia"], "Age": 6}`), &d)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d.Set("Name", "Pugsley")
+	d.Set("Profile.Zodiac", "Gemini")
+	d.Delete("Parents.0")
+	d.Delete("Age")
+	d.Delete("NotExistsKey")
+
+	b, err := json.Marshal(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	equal(t, `{"Name":"Pugsley","Parents":["Morticia"],"Profile":{"Zodiac":"Gemini"}}`, string(b))
+
+	equal(t, false, d.SetOK("Name.First", "Pugsley"))
+	equal(t, true, panics(func() { d.Set("Parents.3", "Fester") }))
+}
+
+func TestD_SetMap(t *testing.T) {
+	t.Parallel()
+
+	var d D
+	d.Set("pet", map[string]any{"name": "Spider", "tags": []any{map[string]any{"b": 2.0, "a": 1.0}}})
+	d.Set("home", M{"street": "Cemetery Lane", "city": "Westfield"})
+
+	equal(t, "Spider", d.Document("pet").StringValue("name"))
+	equalSlice(t, []string{"name", "tags"}, d.Document("pet").Keys())
+	equalSlice(t, []string{"a", "b"}, d.Document("pet.tags.0").Keys())
+	equalSlice(t, []string{"city", "street"}, d.Document("home").Keys())
+	equal(t, true, d.SetOK("home.zip", "00001"))
+	equalSlice(t, []string{"city", "street", "zip"}, d.Document("home").Keys())
+
+	b, err := json.Marshal(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	equal(t, `{"pet":{"name":"Spider","tags":[{"a":1,"b":2}]},"home":{"city":"Westfield","street":"Cemetery Lane","zip":"00001"}}`, string(b))
+}
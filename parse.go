@@ -0,0 +1,495 @@
+package typed
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// maxNestingDepth is the nesting depth beyond which a Parser gives up, to bound
+// its recursion. It matches the limit of encoding/json.
+const maxNestingDepth = 10000
+
+// maxInternedKeys bounds the number of keys a Parser interns, so that the keys of
+// input with unbounded distinct keys, such as ids used as keys, don't pile up.
+const maxInternedKeys = 4096
+
+// A Parser parses JSON text directly into M and A values in a single pass, without
+// building an intermediate map[string]any tree first. The zero value is ready to use.
+// A Parser must not be used concurrently.
+type Parser struct {
+	// UseNumber causes numbers to be parsed as json.Number instead of float64.
+	UseNumber bool
+
+	// InternKeys causes equal object keys to share their storage, across calls
+	// to Parse as well. This saves memory when parsing many objects with the same
+	// keys, such as the elements of a large array of events. The first 4096
+	// distinct keys are interned for the life of the Parser; later keys are not.
+	InternKeys bool
+
+	keys map[string]string
+}
+
+// Parse parses the JSON value in data. Objects are parsed as M and arrays as A.
+// Syntax errors are reported as a *DecodeError.
+func (p *Parser) Parse(data []byte) (any, error) {
+	s := scanner{data: data, p: p}
+	s.skipSpace()
+	v := s.value(0)
+	if s.err == nil {
+		s.skipSpace()
+		if s.i < len(s.data) {
+			s.fail("invalid character %s after top-level value", quoteChar(s.data[s.i]))
+		}
+	}
+	if s.err != nil {
+		return nil, s.err
+	}
+	return v, nil
+}
+
+// kindOf returns the name of the JSON type of v, a parsed value or a json.Token, as
+// used by json.UnmarshalTypeError.
+func kindOf(v any) string {
+	switch v.(type) {
+	case M, D, json.Delim:
+		if v == json.Delim('[') {
+			return "array"
+		}
+		return "object"
+	case A:
+		return "array"
+	case bool:
+		return "bool"
+	case float64, json.Number:
+		return "number"
+	case string:
+		return "string"
+	}
+	return "null"
+}
+
+func (p *Parser) intern(b []byte) string {
+	if !p.InternKeys {
+		return string(b)
+	}
+	if p.keys == nil {
+		p.keys = make(map[string]string)
+	}
+	if k, ok := p.keys[string(b)]; ok {
+		return k
+	}
+	k := string(b)
+	if len(p.keys) < maxInternedKeys {
+		p.keys[k] = k
+	}
+	return k
+}
+
+// scanner holds the state of a call to Parser.Parse.
+type scanner struct {
+	data []byte
+	i    int
+	p    *Parser
+	err  error
+}
+
+func (s *scanner) fail(format string, args ...any) {
+	if s.err == nil {
+		s.err = &DecodeError{Offset: int64(s.i), Err: fmt.Errorf(format, args...)}
+	}
+}
+
+func (s *scanner) failEOF() {
+	if s.err == nil {
+		s.err = &DecodeError{Offset: int64(s.i), Err: errors.New("unexpected end of JSON input")}
+	}
+}
+
+func quoteChar(c byte) string {
+	if c == '\'' {
+		return `'\''`
+	}
+	if c == '"' {
+		return `'"'`
+	}
+	s := strconv.Quote(string(c))
+	return "'" + s[1:len(s)-1] + "'"
+}
+
+func (s *scanner) skipSpace() {
+	for s.i < len(s.data) {
+		switch s.data[s.i] {
+		case ' ', '\t', '\n', '\r':
+			s.i++
+		default:
+			return
+		}
+	}
+}
+
+func (s *scanner) value(depth int) any {
+	if s.i >= len(s.data) {
+		s.failEOF()
+		return nil
+	}
+
+	switch c := s.data[s.i]; c {
+	case '{':
+		if depth >= maxNestingDepth {
+			s.fail("exceeded max depth")
+			return nil
+		}
+		return s.object(depth + 1)
+	case '[':
+		if depth >= maxNestingDepth {
+			s.fail("exceeded max depth")
+			return nil
+		}
+		return s.array(depth + 1)
+	case '"':
+		b := s.string()
+		if s.err != nil {
+			return nil
+		}
+		return string(b)
+	case 't':
+		s.literal("true")
+		return true
+	case 'f':
+		s.literal("false")
+		return false
+	case 'n':
+		s.literal("null")
+		return nil
+	default:
+		if c == '-' || '0' <= c && c <= '9' {
+			return s.number()
+		}
+		s.fail("invalid character %s looking for beginning of value", quoteChar(c))
+		return nil
+	}
+}
+
+func (s *scanner) literal(lit string) {
+	for j := 0; j < len(lit); j++ {
+		if s.i >= len(s.data) {
+			s.failEOF()
+			return
+		}
+		if s.data[s.i] != lit[j] {
+			s.fail("invalid character %s in literal %s (expecting %s)", quoteChar(s.data[s.i]), lit, quoteChar(lit[j]))
+			return
+		}
+		s.i++
+	}
+}
+
+func (s *scanner) object(depth int) any {
+	s.i++ // '{'
+	m := M{}
+
+	s.skipSpace()
+	if s.i < len(s.data) && s.data[s.i] == '}' {
+		s.i++
+		return m
+	}
+
+	for {
+		s.skipSpace()
+		if s.i >= len(s.data) {
+			s.failEOF()
+			return nil
+		}
+		if s.data[s.i] != '"' {
+			s.fail("invalid character %s looking for beginning of object key string", quoteChar(s.data[s.i]))
+			return nil
+		}
+		b := s.string()
+		if s.err != nil {
+			return nil
+		}
+		key := s.p.intern(b)
+
+		s.skipSpace()
+		if s.i >= len(s.data) {
+			s.failEOF()
+			return nil
+		}
+		if s.data[s.i] != ':' {
+			s.fail("invalid character %s after object key", quoteChar(s.data[s.i]))
+			return nil
+		}
+		s.i++
+
+		s.skipSpace()
+		v := s.value(depth)
+		if s.err != nil {
+			return nil
+		}
+		m[key] = v
+
+		s.skipSpace()
+		if s.i >= len(s.data) {
+			s.failEOF()
+			return nil
+		}
+		switch s.data[s.i] {
+		case ',':
+			s.i++
+		case '}':
+			s.i++
+			return m
+		default:
+			s.fail("invalid character %s after object key:value pair", quoteChar(s.data[s.i]))
+			return nil
+		}
+	}
+}
+
+func (s *scanner) array(depth int) any {
+	s.i++ // '['
+	a := A{}
+
+	s.skipSpace()
+	if s.i < len(s.data) && s.data[s.i] == ']' {
+		s.i++
+		return a
+	}
+
+	for {
+		s.skipSpace()
+		v := s.value(depth)
+		if s.err != nil {
+			return nil
+		}
+		a = append(a, v)
+
+		s.skipSpace()
+		if s.i >= len(s.data) {
+			s.failEOF()
+			return nil
+		}
+		switch s.data[s.i] {
+		case ',':
+			s.i++
+		case ']':
+			s.i++
+			return a
+		default:
+			s.fail("invalid character %s after array element", quoteChar(s.data[s.i]))
+			return nil
+		}
+	}
+}
+
+// string scans the string starting at the current quote and returns its
+// contents. The result aliases data if the string holds no escapes.
+func (s *scanner) string() []byte {
+	s.i++ // '"'
+	start := s.i
+	for s.i < len(s.data) {
+		switch c := s.data[s.i]; {
+		case c == '"':
+			b := s.data[start:s.i]
+			s.i++
+			return b
+		case c == '\\':
+			return s.unquote(start)
+		case c >= utf8.RuneSelf:
+			r, size := utf8.DecodeRune(s.data[s.i:])
+			if r == utf8.RuneError && size == 1 {
+				return s.unquote(start)
+			}
+			s.i += size
+		case c < ' ':
+			s.fail("invalid character %s in string literal", quoteChar(c))
+			return nil
+		default:
+			s.i++
+		}
+	}
+	s.failEOF()
+	return nil
+}
+
+// unquote decodes the string starting at start whose contents need rewriting:
+// escapes are decoded and invalid UTF-8 is replaced by utf8.RuneError, as
+// encoding/json does.
+func (s *scanner) unquote(start int) []byte {
+	b := make([]byte, s.i-start, s.i-start+32)
+	copy(b, s.data[start:s.i])
+
+	for s.i < len(s.data) {
+		c := s.data[s.i]
+		switch {
+		case c == '"':
+			s.i++
+			return b
+		case c < ' ':
+			s.fail("invalid character %s in string literal", quoteChar(c))
+			return nil
+		case c >= utf8.RuneSelf:
+			r, size := utf8.DecodeRune(s.data[s.i:])
+			s.i += size
+			b = utf8.AppendRune(b, r)
+		case c != '\\':
+			b = append(b, c)
+			s.i++
+		default:
+			s.i++
+			if s.i >= len(s.data) {
+				s.failEOF()
+				return nil
+			}
+			switch e := s.data[s.i]; e {
+			case '"', '\\', '/':
+				b = append(b, e)
+			case 'b':
+				b = append(b, '\b')
+			case 'f':
+				b = append(b, '\f')
+			case 'n':
+				b = append(b, '\n')
+			case 'r':
+				b = append(b, '\r')
+			case 't':
+				b = append(b, '\t')
+			case 'u':
+				r := s.hex4()
+				if s.err != nil {
+					return nil
+				}
+				if utf16.IsSurrogate(r) {
+					// A character outside the BMP is escaped as a surrogate pair.
+					r2 := utf8.RuneError
+					if s.i+2 < len(s.data) && s.data[s.i+1] == '\\' && s.data[s.i+2] == 'u' {
+						save := s.i
+						s.i += 2
+						if r2 = utf16.DecodeRune(r, s.hex4()); s.err != nil {
+							return nil
+						}
+						if r2 == utf8.RuneError {
+							s.i = save
+						}
+					}
+					r = r2
+				}
+				b = utf8.AppendRune(b, r)
+			default:
+				s.fail("invalid character %s in string escape code", quoteChar(e))
+				return nil
+			}
+			s.i++
+		}
+	}
+	s.failEOF()
+	return nil
+}
+
+// hex4 decodes the four hexadecimal digits following the current 'u', leaving
+// the scanner at the last digit.
+func (s *scanner) hex4() rune {
+	if s.i+4 >= len(s.data) {
+		s.i = len(s.data)
+		s.failEOF()
+		return 0
+	}
+
+	var r rune
+	for j := 1; j <= 4; j++ {
+		c := s.data[s.i+j]
+		switch {
+		case '0' <= c && c <= '9':
+			c -= '0'
+		case 'a' <= c && c <= 'f':
+			c = c - 'a' + 10
+		case 'A' <= c && c <= 'F':
+			c = c - 'A' + 10
+		default:
+			s.i += j
+			s.fail("invalid character %s in \\u hexadecimal character escape", quoteChar(s.data[s.i]))
+			return 0
+		}
+		r = r<<4 | rune(c)
+	}
+	s.i += 4
+	return r
+}
+
+func (s *scanner) number() any {
+	start := s.i
+	if !s.scanNumber() {
+		return nil
+	}
+
+	lit := string(s.data[start:s.i])
+	if s.p.UseNumber {
+		return json.Number(lit)
+	}
+	f, err := strconv.ParseFloat(lit, 64)
+	if err != nil {
+		s.i = start
+		s.fail("cannot parse number %s: %v", lit, err)
+		return nil
+	}
+	return f
+}
+
+// scanNumber scans the number at the current position.
+func (s *scanner) scanNumber() bool {
+	if s.data[s.i] == '-' {
+		s.i++
+	}
+
+	if s.i >= len(s.data) {
+		s.failEOF()
+		return false
+	}
+	switch c := s.data[s.i]; {
+	case c == '0':
+		s.i++
+	case '1' <= c && c <= '9':
+		s.digits()
+	default:
+		s.fail("invalid character %s in numeric literal", quoteChar(c))
+		return false
+	}
+
+	if s.i < len(s.data) && s.data[s.i] == '.' {
+		s.i++
+		if !s.digits() {
+			return false
+		}
+	}
+	if s.i < len(s.data) && (s.data[s.i] == 'e' || s.data[s.i] == 'E') {
+		s.i++
+		if s.i < len(s.data) && (s.data[s.i] == '+' || s.data[s.i] == '-') {
+			s.i++
+		}
+		if !s.digits() {
+			return false
+		}
+	}
+	return true
+}
+
+// digits scans one or more decimal digits.
+func (s *scanner) digits() bool {
+	start := s.i
+	for s.i < len(s.data) && '0' <= s.data[s.i] && s.data[s.i] <= '9' {
+		s.i++
+	}
+	if s.i == start {
+		if s.i >= len(s.data) {
+			s.failEOF()
+		} else {
+			s.fail("invalid character %s in numeric literal", quoteChar(s.data[s.i]))
+		}
+		return false
+	}
+	return true
+}
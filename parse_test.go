@@ -0,0 +1,226 @@
+package typed
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+var parserTests = []string{
+	`null`,
+	`true`,
+	` false `,
+	`0`,
+	`-12.5e+3`,
+	`1E-2`,
+	`""`,
+	`"Wednesday"`,
+	`"tab\tquote\"slash\/backslash\\"`,
+	`"été 😀"`,
+	`"lone \ud83d surrogate"`,
+	`"\ud83dA"`,
+	"\"caf\xc3\xa9 \xff\"",
+	`[]`,
+	`{}`,
+	`[1, "two", [3], {"four": 4}]`,
+	`{"Name": "Wednesday", "Age": 6, "Parents": ["Gomez", "Morticia"], "Pet": null}`,
+	`{"a": 1, "a": 2}`,
+}
+
+func TestParser(t *testing.T) {
+	t.Parallel()
+
+	for _, input := range parserTests {
+		var p Parser
+		got, err := p.Parse([]byte(input))
+		if err != nil {
+			t.Errorf("%s: %v", input, err)
+			continue
+		}
+
+		var want any
+		if err := json.Unmarshal([]byte(input), &want); err != nil {
+			t.Fatal(err)
+		}
+		if want = Wrap(want); !reflect.DeepEqual(want, got) {
+			t.Errorf("%s: want %#v; got %#v", input, want, got)
+		}
+	}
+}
+
+func TestParser_Errors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input  string
+		offset int64
+	}{
+		{``, 0},
+		{`{"a" 1}`, 5},
+		{`[1, 2`, 5},
+		{`[1 2]`, 3},
+		{`{"a": tru}`, 9},
+		{`"abc`, 4},
+		{"\"a\nb\"", 2},
+		{`"\x"`, 2},
+		{`"\u12G4"`, 5},
+		{`01`, 1},
+		{`-`, 1},
+		{`1.`, 2},
+		{`1e`, 2},
+		{`{"a": 1} x`, 9},
+		{`{1: 2}`, 1},
+	}
+	for _, tc := range tests {
+		var p Parser
+		_, err := p.Parse([]byte(tc.input))
+
+		var de *DecodeError
+		if !errors.As(err, &de) {
+			t.Errorf("%q: want *DecodeError; got %v", tc.input, err)
+			continue
+		}
+		if de.Offset != tc.offset {
+			t.Errorf("%q: want offset %d; got %d (%v)", tc.input, tc.offset, de.Offset, err)
+		}
+		if json.Valid([]byte(tc.input)) {
+			t.Errorf("%q: valid for encoding/json", tc.input)
+		}
+	}
+}
+
+func TestParser_MaxDepth(t *testing.T) {
+	t.Parallel()
+
+	input := bytes.Repeat([]byte("["), maxNestingDepth+1)
+	var p Parser
+	_, err := p.Parse(input)
+	equal(t, true, err != nil)
+}
+
+func TestParser_Options(t *testing.T) {
+	t.Parallel()
+
+	p := Parser{UseNumber: true, InternKeys: true}
+	v, err := p.Parse([]byte(`[{"id": 12345678901234567}, {"id": 2}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a := v.(A)
+	equal(t, json.Number("12345678901234567"), a[0].(M)["id"].(json.Number))
+	equal(t, 1, len(p.keys))
+
+	for i := range maxInternedKeys + 10 {
+		if _, err := p.Parse([]byte(fmt.Sprintf(`{"k%d": %d}`, i, i))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	equal(t, maxInternedKeys, len(p.keys))
+	v, err = p.Parse([]byte(`{"id": 1, "new": 2}`))
+	equal(t, nil, err)
+	equal(t, 2, v.(M).AsInt("new"))
+}
+
+func TestM_UnmarshalJSON_NotDocument(t *testing.T) {
+	t.Parallel()
+
+	var m M
+	err := json.Unmarshal([]byte(`[1]`), &m)
+	var ute *json.UnmarshalTypeError
+	equal(t, true, errors.As(err, &ute))
+	equal(t, "array", ute.Value)
+}
+
+func FuzzParser(f *testing.F) {
+	for _, input := range parserTests {
+		f.Add([]byte(input))
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var p Parser
+		got, err := p.Parse(data)
+
+		var want any
+		if json.Unmarshal(data, &want) != nil {
+			if err == nil {
+				t.Fatalf("%q: want error; got %#v", data, got)
+			}
+			return
+		}
+		if err != nil {
+			t.Fatalf("%q: %v", data, err)
+		}
+		if want = Wrap(want); !reflect.DeepEqual(want, got) {
+			t.Fatalf("%q: want %#v; got %#v", data, want, got)
+		}
+	})
+}
+
+func benchmarkEvents(n int) []byte {
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		fmt.Fprintf(&buf, `{"id": %d, "type": "order.paid", "tenant": "acme", "amount": %d.25, `+
+			`"tags": ["a", "b"], "meta": {"source": "web", "retries": 0, "ok": true}}`, i, i*3)
+	}
+	buf.WriteByte(']')
+	return buf.Bytes()
+}
+
+func BenchmarkUnmarshal(b *testing.B) {
+	data := benchmarkEvents(1000)
+
+	b.Run("Parser", func(b *testing.B) {
+		b.ReportAllocs()
+		b.SetBytes(int64(len(data)))
+		for i := 0; i < b.N; i++ {
+			var a A
+			if err := a.UnmarshalJSON(data); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	// json.Unmarshal validates the input before calling A.UnmarshalJSON, so this is
+	// the cost callers see.
+	b.Run("Unmarshal", func(b *testing.B) {
+		b.ReportAllocs()
+		b.SetBytes(int64(len(data)))
+		for i := 0; i < b.N; i++ {
+			var a A
+			if err := json.Unmarshal(data, &a); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("InternKeys", func(b *testing.B) {
+		b.ReportAllocs()
+		b.SetBytes(int64(len(data)))
+		p := Parser{InternKeys: true}
+		for i := 0; i < b.N; i++ {
+			if _, err := p.Parse(data); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("TwoPass", func(b *testing.B) {
+		b.ReportAllocs()
+		b.SetBytes(int64(len(data)))
+		for i := 0; i < b.N; i++ {
+			var a []any
+			if err := json.Unmarshal(data, &a); err != nil {
+				b.Fatal(err)
+			}
+			_ = Wrap(a)
+		}
+	})
+}
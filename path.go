@@ -0,0 +1,458 @@
+package typed
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Path is the syntax of the keys taken by the accessors: keys concatenated with ".",
+// such as "spec.template.name". Within a key, a backslash escapes the following
+// character, so "a\\.b" is the single key "a.b". A key can also be written as a JSON
+// string in brackets, either as a segment of its own or directly following the
+// previous key, such as `labels["app.kubernetes.io/name"]`.
+//
+// Within an array, a key is the index of an element; negative indexes count from the
+// end, so "items.-1" is the last element. A key "i:j" is the slice of the array from
+// index i up to index j, either of which can be omitted or negative, and yields an A.
+//
+// Any string is a Path, so paths need no conversion. ParsePath and MustPath compile a
+// path once, in a bounded registry, so that lookups with it skip parsing it; otherwise
+// a path holding no backslash or bracket is read without being parsed up front.
+type Path = string
+
+// A PathSyntaxError describes a malformed path.
+type PathSyntaxError struct {
+	Path   string
+	Offset int // offset of the error within Path
+	Msg    string
+}
+
+func (e *PathSyntaxError) Error() string {
+	return fmt.Sprintf("typed: invalid path %q: %s (offset %d)", e.Path, e.Msg, e.Offset)
+}
+
+// EscapeKey returns key escaped for use as a key of a path, so that dots, brackets and
+// backslashes within it are taken literally.
+func EscapeKey(key string) string {
+	if !strings.ContainsAny(key, `.[\`) {
+		return key
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(key); i++ {
+		switch c := key[i]; c {
+		case '.', '[', '\\':
+			b.WriteByte('\\')
+		}
+		b.WriteByte(key[i])
+	}
+	return b.String()
+}
+
+// A segment is a key of a path.
+type segment struct {
+	key   string
+	index int // key as an array index
+	ok    bool
+
+	// slice reports whether key is a slice lo:hi.
+	slice        bool
+	lo, hi       int
+	hasLo, hasHi bool
+
+	end int // offset of the end of the segment within the path
+}
+
+// maxCompiledPaths bounds the number of paths compiled by ParsePath and MustPath.
+const maxCompiledPaths = 1024
+
+// ErrTooManyPaths is returned by ParsePath once the registry of compiled paths is full.
+var ErrTooManyPaths = errors.New("typed: too many compiled paths")
+
+// compiledPaths maps the paths compiled by ParsePath and MustPath to their segments.
+// It is replaced as a whole on every change, so lookups need no locking; its entries
+// are never removed.
+var (
+	compiledPaths atomic.Pointer[map[Path][]segment]
+	compileMu     sync.Mutex
+)
+
+// ParsePath checks the syntax of path and compiles it. Lookups with the returned Path,
+// which is path itself, use the compiled segments instead of parsing it. Syntax errors
+// are reported as a *PathSyntaxError.
+//
+// Compiled paths are kept for the life of the process, in a registry shared by the
+// whole program and bounded to 1024 paths; once it is full, ParsePath returns
+// ErrTooManyPaths for the paths not compiled yet. ParsePath is meant for the fixed
+// paths of a program used many times, not for paths supplied by callers.
+func ParsePath(path string) (Path, error) {
+	if _, ok := compiled(path); ok {
+		return path, nil
+	}
+	segs, err := parsePath(path)
+	if err != nil {
+		return "", err
+	}
+
+	compileMu.Lock()
+	defer compileMu.Unlock()
+
+	old := compiledPaths.Load()
+	if old == nil {
+		old = new(map[Path][]segment)
+	}
+	if _, ok := (*old)[path]; ok {
+		return path, nil
+	}
+	if len(*old) >= maxCompiledPaths {
+		return "", fmt.Errorf("%w: %q", ErrTooManyPaths, path)
+	}
+	m := make(map[Path][]segment, len(*old)+1)
+	for k, v := range *old {
+		m[k] = v
+	}
+	m[path] = segs
+	compiledPaths.Store(&m)
+	return path, nil
+}
+
+// MustPath is like ParsePath but panics if path is malformed or the registry is full.
+// It is meant for the paths of package-level variables:
+//
+//	var appName = typed.MustPath(`metadata.labels["app.kubernetes.io/name"]`)
+func MustPath(path string) Path {
+	p, err := ParsePath(path)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// compiled returns the segments of path if it was compiled by ParsePath.
+func compiled(path Path) ([]segment, bool) {
+	m := compiledPaths.Load()
+	if m == nil {
+		return nil, false
+	}
+	segs, ok := (*m)[path]
+	return segs, ok
+}
+
+// plain reports whether path can be split at its dots without being parsed.
+func plain(path Path) bool {
+	return strings.IndexByte(path, '\\') < 0 && strings.IndexByte(path, '[') < 0
+}
+
+// parsePath splits path into its segments.
+func parsePath(path Path) ([]segment, error) {
+	segs := make([]segment, 0, strings.Count(path, ".")+1)
+	for i := 0; ; {
+		var (
+			seg segment
+			err error
+		)
+		if i < len(path) && path[i] == '[' {
+			seg, i, err = parseQuoted(path, i)
+		} else {
+			seg, i, err = parsePlain(path, i)
+		}
+		if err != nil {
+			return nil, err
+		}
+		segs = append(segs, seg)
+
+		if i == len(path) {
+			return segs, nil
+		}
+		switch path[i] {
+		case '.':
+			i++
+		case '[':
+		default:
+			return nil, &PathSyntaxError{Path: path, Offset: i, Msg: fmt.Sprintf("invalid character %s after ']'", quoteChar(path[i]))}
+		}
+	}
+}
+
+// parsePlain parses the key starting at offset i, up to the next unescaped dot or
+// bracket, and returns the offset following it.
+func parsePlain(path Path, i int) (segment, int, error) {
+	start := i
+	var b []byte
+	for ; i < len(path) && path[i] != '.' && path[i] != '['; i++ {
+		if path[i] != '\\' {
+			if b != nil {
+				b = append(b, path[i])
+			}
+			continue
+		}
+
+		if i+1 == len(path) {
+			return segment{}, 0, &PathSyntaxError{Path: path, Offset: i, Msg: "trailing backslash"}
+		}
+		if b == nil {
+			b = []byte(path[start:i])
+		}
+		i++
+		b = append(b, path[i])
+	}
+
+	key := path[start:i]
+	if b != nil {
+		key = string(b)
+	}
+	return newSegment(key, i), i, nil
+}
+
+// parseQuoted parses the bracket-quoted key starting at offset i, and returns the
+// offset following it.
+func parseQuoted(path Path, i int) (segment, int, error) {
+	if i+1 >= len(path) || path[i+1] != '"' {
+		return segment{}, 0, &PathSyntaxError{Path: path, Offset: i + 1, Msg: "expected '\"' after '['"}
+	}
+
+	s := scanner{data: []byte(path), i: i + 1}
+	b := s.string()
+	if s.err != nil {
+		de := s.err.(*DecodeError)
+		return segment{}, 0, &PathSyntaxError{Path: path, Offset: int(de.Offset), Msg: de.Err.Error()}
+	}
+	if s.i >= len(path) || path[s.i] != ']' {
+		return segment{}, 0, &PathSyntaxError{Path: path, Offset: s.i, Msg: "expected ']' after quoted key"}
+	}
+	return segment{key: string(b), end: s.i + 1}, s.i + 1, nil
+}
+
+// segments returns the segments of path, compiled or not.
+func segments(path Path) ([]segment, error) {
+	if segs, ok := compiled(path); ok {
+		return segs, nil
+	}
+	return parsePath(path)
+}
+
+// pathKeys returns the keys of path.
+func pathKeys(path Path) ([]string, error) {
+	segs, err := segments(path)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, len(segs))
+	for i, seg := range segs {
+		keys[i] = seg.key
+	}
+	return keys, nil
+}
+
+func newSegment(key string, end int) segment {
+	seg := segment{key: key, end: end}
+	if i := strings.IndexByte(key, ':'); i >= 0 {
+		lo, hi := key[:i], key[i+1:]
+		var okLo, okHi bool
+		seg.lo, okLo = parseIndex(lo)
+		seg.hi, okHi = parseIndex(hi)
+		seg.hasLo, seg.hasHi = lo != "", hi != ""
+		seg.slice = (okLo || !seg.hasLo) && (okHi || !seg.hasHi)
+		return seg
+	}
+	seg.index, seg.ok = parseIndex(key)
+	return seg
+}
+
+// parseIndex parses k as strconv.Atoi does, without allocating an error if it is
+// not a number.
+func parseIndex(k string) (int, bool) {
+	if k == "" || len(k) > 18 {
+		return slowParseIndex(k)
+	}
+
+	n := 0
+	for i := 0; i < len(k); i++ {
+		c := k[i]
+		if c < '0' || c > '9' {
+			return slowParseIndex(k)
+		}
+		n = n*10 + int(c-'0')
+	}
+	return n, true
+}
+
+func slowParseIndex(k string) (int, bool) {
+	digits := strings.TrimLeft(k, "+-")
+	if len(digits) == 0 || len(k)-len(digits) > 1 || strings.Trim(digits, "0123456789") != "" {
+		return -1, false
+	}
+	n, err := strconv.Atoi(k)
+	return n, err == nil
+}
+
+// element returns the index within an array of length n of the element seg refers
+// to, counting negative indexes from the end.
+func (seg segment) element(n int) (int, bool) {
+	i := seg.index
+	if i < 0 {
+		i += n
+	}
+	return i, seg.ok && !seg.slice && 0 <= i && i < n
+}
+
+// bounds returns the bounds within an array of length n of the slice seg refers to.
+func (seg segment) bounds(n int) (lo, hi int) {
+	clamp := func(i int, has bool, def int) int {
+		if !has {
+			return def
+		}
+		if i < 0 {
+			i += n
+		}
+		return min(max(i, 0), n)
+	}
+	lo, hi = clamp(seg.lo, seg.hasLo, 0), clamp(seg.hi, seg.hasHi, n)
+	return lo, max(lo, hi)
+}
+
+// Lookup results, telling why a lookup failed.
+const (
+	lookupFound = iota
+	lookupNotFound
+	lookupBadType
+	lookupBadIndex
+	lookupBadPath
+)
+
+// walk returns the value for path within a. If the lookup fails, it returns the
+// index of the segment where it failed and the reason. It allocates nothing if path
+// is compiled or plain, and the value is not a slice of an array.
+func walk(a any, path Path) (v any, n int, result int) {
+	segs, ok := compiled(path)
+	if !ok && plain(path) {
+		for start := 0; ; n++ {
+			end := strings.IndexByte(path[start:], '.')
+			if end < 0 {
+				end = len(path)
+			} else {
+				end += start
+			}
+			if a, result = child(a, newSegment(path[start:end], end)); result != lookupFound {
+				return nil, n, result
+			}
+			if end == len(path) {
+				return a, 0, lookupFound
+			}
+			start = end + 1
+		}
+	}
+
+	if !ok {
+		var err error
+		if segs, err = parsePath(path); err != nil {
+			return nil, 0, lookupBadPath
+		}
+	}
+	for n, seg := range segs {
+		if a, result = child(a, seg); result != lookupFound {
+			return nil, n, result
+		}
+	}
+	return a, 0, lookupFound
+}
+
+// child returns the value for seg within a.
+func child(a any, seg segment) (any, int) {
+	switch x := a.(type) {
+	case M:
+		v, ok := x[seg.key]
+		if !ok {
+			return nil, lookupNotFound
+		}
+		return v, lookupFound
+	case D:
+		j := x.index(seg.key)
+		if j < 0 {
+			return nil, lookupNotFound
+		}
+		return x[j].Value, lookupFound
+	case A:
+		if seg.slice {
+			lo, hi := seg.bounds(len(x))
+			return x[lo:hi:hi], lookupFound
+		}
+		if !seg.ok {
+			return nil, lookupBadIndex
+		}
+		i, ok := seg.element(len(x))
+		if !ok {
+			return nil, lookupNotFound
+		}
+		return x[i], lookupFound
+	}
+	return nil, lookupBadType
+}
+
+// lookupError returns the error of a lookup of path within a which failed at
+// segment n for the given reason.
+func lookupError(a any, path Path, n, result int) error {
+	segs, err := segments(path)
+	if err != nil {
+		return err
+	}
+	for _, seg := range segs[:n] {
+		a, _ = child(a, seg)
+	}
+
+	switch seg := segs[n]; result {
+	case lookupNotFound:
+		return fmt.Errorf("not found key %q", path[:seg.end])
+	case lookupBadIndex:
+		if _, err := strconv.Atoi(seg.key); err != nil {
+			return err
+		}
+		return fmt.Errorf("invalid index %q", seg.key)
+	}
+	return fmt.Errorf("unknown type %T", a)
+}
+
+// as asserts that v is of type E, as v.(E) does, except that a json.Number is
+// converted if E is float64.
+func as[E any](v any) (E, bool) {
+	if e, ok := v.(E); ok {
+		return e, true
+	}
+
+	var e E
+	if n, ok := v.(json.Number); ok {
+		if p, ok := any(&e).(*float64); ok {
+			f, err := n.Float64()
+			*p = f
+			return e, err == nil
+		}
+	}
+	return e, false
+}
+
+// convert is the same as as, except it returns an error instead of a boolean.
+func convert[E any](v any) (E, error) {
+	e, ok := as[E](v)
+	if ok {
+		return e, nil
+	}
+
+	if n, ok := v.(json.Number); ok {
+		if _, ok := any(e).(float64); ok {
+			_, err := n.Float64()
+			return e, err
+		}
+	}
+	have := "nil"
+	if v != nil {
+		have = reflect.TypeOf(v).String()
+	}
+	return e, fmt.Errorf("interface conversion: interface {} is %s, not %s", have, reflect.TypeFor[E]())
+}
@@ -0,0 +1,269 @@
+package typed
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"testing"
+)
+
+var pathDoc = M{
+	"name": "Wednesday",
+	"age":  6.0,
+	"profile": M{
+		"zodiac": "Scorpio",
+		"pets":   A{M{"name": "Spider", "legs": 8.0}},
+	},
+	"parents": A{"Gomez", "Morticia"},
+	"big":     json.Number("12"),
+}
+
+func TestMustPath(t *testing.T) {
+	t.Parallel()
+
+	p := MustPath("profile.pets.0.name")
+	equal(t, "profile.pets.0.name", p)
+	equal(t, "Spider", pathDoc.StringValue(p))
+
+	segs, ok := compiled(p)
+	equal(t, true, ok)
+	equal(t, 4, len(segs))
+	equal(t, 0, segs[2].index)
+	equal(t, len(p), segs[3].end)
+
+	equal(t, MustPath(p), p)
+}
+
+func TestLookupErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		key string
+		err string
+	}{
+		{"missing", `not found key "missing"`},
+		{"profile.missing.name", `not found key "profile.missing"`},
+		{"parents.2", `not found key "parents.2"`},
+		{"parents.-3", `not found key "parents.-3"`},
+		{"parents.x", `strconv.Atoi: parsing "x": invalid syntax`},
+		{"name.first", `unknown type string`},
+		{"age", `interface conversion: interface {} is float64, not string`},
+	}
+	for _, tc := range tests {
+		for _, key := range []string{tc.key, MustPath(tc.key)} {
+			_, err := lookupErr[string](pathDoc, key)
+			if err == nil || err.Error() != tc.err {
+				t.Errorf("%s: want %s; got %v", key, tc.err, err)
+			}
+		}
+	}
+
+	equal(t, 12, pathDoc.AsInt("big"))
+}
+
+func TestParseIndex(t *testing.T) {
+	t.Parallel()
+
+	for _, k := range []string{"0", "12", "007", "+3", "-1", "", "x", "1x", "+-1", "--1", "99999999999999999999", "1234567890123456789"} {
+		want, err := strconv.Atoi(k)
+		got, ok := parseIndex(k)
+		equal(t, err == nil, ok)
+		if ok {
+			equal(t, want, got)
+		}
+	}
+}
+
+func TestLookupAllocs(t *testing.T) {
+	keys := []string{"name", "profile.zodiac", "profile.pets.0.name", "profile.pets.1.name", "parents.x", "missing"}
+	for _, key := range keys {
+		for _, key := range []string{key, MustPath(key)} {
+			allocs := testing.AllocsPerRun(100, func() {
+				pathDoc.StringValueOK(key)
+				pathDoc.Exists(key)
+			})
+			if allocs != 0 {
+				t.Errorf("%s: %v allocs", key, allocs)
+			}
+		}
+	}
+
+	a := A{pathDoc}
+	allocs := testing.AllocsPerRun(100, func() {
+		a.StringValueOK("0.profile.pets.0.name")
+	})
+	if allocs != 0 {
+		t.Errorf("A: %v allocs", allocs)
+	}
+}
+
+func BenchmarkLookup(b *testing.B) {
+	doc := M{"a": M{"b": A{M{"c": M{"d": A{"x", "y", "z"}}}}}}
+	a := A{doc}
+	for _, key := range []string{"a", "a.b.0", "a.b.0.c.d.2", "a.b.0.c.missing"} {
+		b.Run(fmt.Sprintf("M/%s", key), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				doc.Exists(key)
+			}
+		})
+		b.Run(fmt.Sprintf("M/Compiled/%s", key), func(b *testing.B) {
+			key := MustPath(key)
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				doc.Exists(key)
+			}
+		})
+		b.Run(fmt.Sprintf("A/%s", key), func(b *testing.B) {
+			key := "0." + key
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				a.Exists(key)
+			}
+		})
+	}
+}
+
+func TestPathGrammar(t *testing.T) {
+	t.Parallel()
+
+	m := M{
+		"metadata": M{
+			"labels": M{"app.kubernetes.io/name": "web", `a\b`: "backslash", "x[0]": "bracket"},
+		},
+		"items": A{"a", "b", "c", "d"},
+		"a.b":   M{"c": 1.0},
+	}
+
+	tests := []struct {
+		key  string
+		want any
+	}{
+		{`metadata.labels["app.kubernetes.io/name"]`, "web"},
+		{`metadata.labels.["app.kubernetes.io/name"]`, "web"},
+		{`["metadata"]["labels"]["app.kubernetes.io/name"]`, "web"},
+		{`metadata.labels.app\.kubernetes\.io/name`, "web"},
+		{`metadata.labels.a\\b`, "backslash"},
+		{`metadata.labels["a\\b"]`, "backslash"},
+		{`metadata.labels.x\[0]`, "bracket"},
+		{`a\.b.c`, 1.0},
+		{`["a.b"].c`, 1.0},
+		{"items.-1", "d"},
+		{"items.-4", "a"},
+		{"items.1:3", A{"b", "c"}},
+		{"items.:2", A{"a", "b"}},
+		{"items.-2:", A{"c", "d"}},
+		{"items.2:1", A{}},
+		{"items.:10", A{"a", "b", "c", "d"}},
+		{"items.1:3.-1", "c"},
+	}
+	for _, tc := range tests {
+		for _, key := range []string{tc.key, MustPath(tc.key)} {
+			got, err := lookupErr[any](m, key)
+			if err != nil {
+				t.Errorf("%s: %v", key, err)
+				continue
+			}
+			if want, ok := tc.want.(A); ok {
+				equalSlice(t, want, got.(A))
+				continue
+			}
+			equal(t, tc.want, got)
+		}
+	}
+
+	equal(t, false, m.Exists("items.-5"))
+	equal(t, false, m.Exists(`metadata.labels["app.kubernetes.io/name"`))
+}
+
+func TestParsePath_Bound(t *testing.T) {
+	// Not parallel: the registry is restored for the other tests.
+	old := compiledPaths.Load()
+	defer compiledPaths.Store(old)
+	compiledPaths.Store(nil)
+
+	for i := range maxCompiledPaths {
+		p, err := ParsePath(fmt.Sprintf("bound.%d", i))
+		equal(t, nil, err)
+		equal(t, fmt.Sprintf("bound.%d", i), p)
+	}
+	equal(t, maxCompiledPaths, len(*compiledPaths.Load()))
+
+	// Paths beyond the bound are rejected; those compiled are still returned.
+	_, err := ParsePath(`bound["x`)
+	var syntaxErr *PathSyntaxError
+	equal(t, true, errors.As(err, &syntaxErr))
+	_, err = ParsePath("bound.-2")
+	equal(t, true, errors.Is(err, ErrTooManyPaths))
+	equal(t, `typed: too many compiled paths: "bound.-2"`, err.Error())
+	equal(t, true, panics(func() { MustPath("bound.-2") }))
+	equal(t, "bound.0", MustPath("bound.0"))
+	equal(t, 1, M{"bound": A{1.0, 2.0}}.AsInt("bound.-2"))
+}
+
+func TestParsePath_Errors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		path   string
+		offset int
+	}{
+		{`a\`, 1},
+		{`a[b]`, 2},
+		{`a["b"`, 5},
+		{`a["b]`, 5},
+		{`a["b"]c`, 6},
+		{`a["\x"]`, 4},
+	}
+	for _, tc := range tests {
+		_, err := ParsePath(tc.path)
+		var pe *PathSyntaxError
+		if !errors.As(err, &pe) {
+			t.Errorf("%s: want *PathSyntaxError; got %v", tc.path, err)
+			continue
+		}
+		equal(t, tc.path, pe.Path)
+		if pe.Offset != tc.offset {
+			t.Errorf("%s: want offset %d; got %d (%v)", tc.path, tc.offset, pe.Offset, err)
+		}
+
+		_, err = lookupErr[any](M{}, tc.path)
+		equal(t, true, errors.As(err, &pe))
+	}
+
+	equal(t, true, panics(func() { MustPath(`a["b"`) }))
+}
+
+func TestEscapeKey(t *testing.T) {
+	t.Parallel()
+
+	for _, key := range []string{"plain", "app.kubernetes.io/name", `a\b`, "x[0]", "-1", "1:3", ""} {
+		m := M{"k": M{key: true}}
+		path := "k." + EscapeKey(key)
+		equal(t, true, m.Bool(path))
+
+		segs, err := parsePath(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		equal(t, key, segs[1].key)
+	}
+	equal(t, `app\.kubernetes\.io/name`, EscapeKey("app.kubernetes.io/name"))
+}
+
+func TestSetDelete_PathGrammar(t *testing.T) {
+	t.Parallel()
+
+	m := M{"items": A{"a", "b", "c", "d"}}
+	m.Set("items.-1", "z")
+	m.Set(`labels["app.kubernetes.io/name"]`, "web")
+	equal(t, "z", m.StringValue("items.3"))
+	equal(t, "web", m.Document("labels").StringValue(`app\.kubernetes\.io/name`))
+
+	m.Delete("items.1:3")
+	equalSlice(t, A{"a", "z"}, m.Array("items"))
+	m.Delete("items.-1")
+	equalSlice(t, A{"a"}, m.Array("items"))
+	equal(t, false, m.SetOK(`a["b`, 1))
+}
@@ -0,0 +1,269 @@
+package typed
+
+// This file holds the scanner methods that navigate raw JSON text without
+// building values, used to read parts of a document without parsing all of it.
+
+// skip skips the value at the current position, checking its syntax.
+func (s *scanner) skip(depth int) {
+	if s.i >= len(s.data) {
+		s.failEOF()
+		return
+	}
+
+	switch c := s.data[s.i]; c {
+	case '{', '[':
+		if depth >= maxNestingDepth {
+			s.fail("exceeded max depth")
+			return
+		}
+		s.skipContainer(c, depth+1)
+	case '"':
+		s.skipString()
+	case 't':
+		s.literal("true")
+	case 'f':
+		s.literal("false")
+	case 'n':
+		s.literal("null")
+	default:
+		if c == '-' || '0' <= c && c <= '9' {
+			s.scanNumber()
+			return
+		}
+		s.fail("invalid character %s looking for beginning of value", quoteChar(c))
+	}
+}
+
+func (s *scanner) skipContainer(open byte, depth int) {
+	end := byte('}')
+	if open == '[' {
+		end = ']'
+	}
+
+	s.i++
+	s.skipSpace()
+	if s.i < len(s.data) && s.data[s.i] == end {
+		s.i++
+		return
+	}
+
+	for s.err == nil {
+		s.skipSpace()
+		if open == '{' {
+			if !s.member() {
+				return
+			}
+		}
+		s.skip(depth)
+		if !s.next(end) {
+			return
+		}
+	}
+}
+
+// member scans an object key and the following colon, leaving the scanner at
+// the start of the value.
+func (s *scanner) member() bool {
+	_, ok := s.key()
+	return ok
+}
+
+// key scans an object key and the following colon, leaving the scanner at the
+// start of the value, and returns the key. The result aliases data if the key holds
+// no escapes.
+func (s *scanner) key() ([]byte, bool) {
+	if s.i >= len(s.data) {
+		s.failEOF()
+		return nil, false
+	}
+	if s.data[s.i] != '"' {
+		s.fail("invalid character %s looking for beginning of object key string", quoteChar(s.data[s.i]))
+		return nil, false
+	}
+	k := s.string()
+	if s.err != nil {
+		return nil, false
+	}
+
+	s.skipSpace()
+	if s.i >= len(s.data) {
+		s.failEOF()
+		return nil, false
+	}
+	if s.data[s.i] != ':' {
+		s.fail("invalid character %s after object key", quoteChar(s.data[s.i]))
+		return nil, false
+	}
+	s.i++
+	s.skipSpace()
+	return k, true
+}
+
+// next scans the separator after an element of a container closed by end. It
+// reports whether another element follows.
+func (s *scanner) next(end byte) bool {
+	if s.err != nil {
+		return false
+	}
+
+	s.skipSpace()
+	if s.i >= len(s.data) {
+		s.failEOF()
+		return false
+	}
+	switch s.data[s.i] {
+	case ',':
+		s.i++
+		return true
+	case end:
+		s.i++
+		return false
+	}
+	if end == '}' {
+		s.fail("invalid character %s after object key:value pair", quoteChar(s.data[s.i]))
+	} else {
+		s.fail("invalid character %s after array element", quoteChar(s.data[s.i]))
+	}
+	return false
+}
+
+// skipString skips the string at the current position without decoding it.
+func (s *scanner) skipString() {
+	s.i++ // '"'
+	for s.i < len(s.data) {
+		switch c := s.data[s.i]; {
+		case c == '"':
+			s.i++
+			return
+		case c == '\\':
+			s.i++
+			if s.i >= len(s.data) {
+				s.failEOF()
+				return
+			}
+			switch e := s.data[s.i]; e {
+			case '"', '\\', '/', 'b', 'f', 'n', 'r', 't':
+				s.i++
+			case 'u':
+				s.hex4()
+				s.i++
+			default:
+				s.fail("invalid character %s in string escape code", quoteChar(e))
+				return
+			}
+		case c < ' ':
+			s.fail("invalid character %s in string literal", quoteChar(c))
+			return
+		default:
+			s.i++
+		}
+	}
+	s.failEOF()
+}
+
+// find moves the scanner from the value at the current position to the value for
+// segs within it, and reports whether it exists. Like M, it uses the last value of
+// a key occurring more than once in an object. Slices of arrays are not found.
+func (s *scanner) find(segs []segment) bool {
+	for depth, seg := range segs {
+		s.skipSpace()
+		if s.i >= len(s.data) {
+			s.failEOF()
+			return false
+		}
+
+		var found bool
+		switch s.data[s.i] {
+		case '{':
+			found = s.findKey(seg.key, depth)
+		case '[':
+			if seg.ok && !seg.slice {
+				n := seg.index
+				if n < 0 {
+					n += s.count()
+				}
+				found = s.findIndex(n, depth)
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	s.skipSpace()
+	return s.err == nil
+}
+
+// count returns the number of elements of the array at the current position,
+// without moving the scanner.
+func (s *scanner) count() int {
+	t := scanner{data: s.data, i: s.i + 1}
+	t.skipSpace()
+	if t.i < len(t.data) && t.data[t.i] == ']' {
+		return 0
+	}
+
+	n := 0
+	for t.err == nil {
+		t.skipSpace()
+		t.skip(1)
+		n++
+		if !t.next(']') {
+			break
+		}
+	}
+	return n
+}
+
+// findKey moves the scanner from the object at the current position to the value
+// for key.
+func (s *scanner) findKey(key string, depth int) bool {
+	s.i++ // '{'
+	s.skipSpace()
+	if s.i < len(s.data) && s.data[s.i] == '}' {
+		s.i++
+		return false
+	}
+
+	found := -1
+	for s.err == nil {
+		s.skipSpace()
+		k, ok := s.key()
+		if !ok {
+			return false
+		}
+		if string(k) == key {
+			found = s.i
+		}
+		s.skip(depth + 1)
+		if !s.next('}') {
+			break
+		}
+	}
+	if s.err != nil || found < 0 {
+		return false
+	}
+	s.i = found
+	return true
+}
+
+// findIndex moves the scanner from the array at the current position to its
+// element with index n.
+func (s *scanner) findIndex(n, depth int) bool {
+	s.i++ // '['
+	s.skipSpace()
+	if n < 0 || s.i < len(s.data) && s.data[s.i] == ']' {
+		return false
+	}
+
+	for i := 0; s.err == nil; i++ {
+		s.skipSpace()
+		if i == n {
+			return true
+		}
+		s.skip(depth + 1)
+		if !s.next(']') {
+			return false
+		}
+	}
+	return false
+}
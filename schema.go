@@ -0,0 +1,281 @@
+package typed
+
+import (
+	"encoding/json"
+	"net/mail"
+	"regexp"
+	"sort"
+	"time"
+)
+
+// SchemaDialect is the JSON Schema dialect InferSchema declares in its output.
+const SchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// maxEnumValues is the largest number of distinct strings InferSchema
+// reports as an enum.
+const maxEnumValues = 10
+
+// InferSchema infers a JSON Schema from the given sample documents, returned as M.
+//
+// The types observed for every path are merged, integers are distinguished from
+// other numbers, and values observed as null mark their path nullable by adding
+// "null" to its types. Keys found in every object at a path are listed as
+// required. Strings that repeat a small set of values are reported as an enum, and
+// strings that all look like RFC 3339 times, UUIDs or email addresses get the
+// corresponding format.
+//
+// Samples are typically M or A values, but map[string]any, []any and scalars are
+// accepted as well.
+func InferSchema(samples ...any) M {
+	var root schemaNode
+	for _, sample := range samples {
+		root.add(sample)
+	}
+
+	schema := M{}
+	if root.count > 0 {
+		schema = root.schema()
+	}
+	schema["$schema"] = SchemaDialect
+	return schema
+}
+
+// schemaTypes is a set of JSON Schema types.
+type schemaTypes uint8
+
+const (
+	typeArray schemaTypes = 1 << iota
+	typeBoolean
+	typeInteger
+	typeNumber
+	typeObject
+	typeString
+	typeNull
+)
+
+var schemaTypeNames = []struct {
+	t    schemaTypes
+	name string
+}{
+	{typeArray, "array"},
+	{typeBoolean, "boolean"},
+	{typeInteger, "integer"},
+	{typeNumber, "number"},
+	{typeObject, "object"},
+	{typeString, "string"},
+	{typeNull, "null"},
+}
+
+// stringFormats is a set of JSON Schema string formats.
+type stringFormats uint8
+
+const (
+	formatDateTime stringFormats = 1 << iota
+	formatUUID
+	formatEmail
+)
+
+var stringFormatNames = []struct {
+	f    stringFormats
+	name string
+}{
+	{formatDateTime, "date-time"},
+	{formatUUID, "uuid"},
+	{formatEmail, "email"},
+}
+
+var uuidRegexp = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+func formatsOf(s string) (f stringFormats) {
+	if _, err := time.Parse(time.RFC3339, s); err == nil {
+		f |= formatDateTime
+	}
+	if uuidRegexp.MatchString(s) {
+		f |= formatUUID
+	}
+	if addr, err := mail.ParseAddress(s); err == nil && addr.Name == "" && addr.Address == s {
+		f |= formatEmail
+	}
+	return f
+}
+
+// schemaNode accumulates the values observed at one path.
+type schemaNode struct {
+	count int
+	types schemaTypes
+
+	objects    int
+	properties map[string]*schemaNode
+
+	items *schemaNode
+
+	strings int
+	formats stringFormats
+	values  map[string]bool
+	tooMany bool
+}
+
+func (n *schemaNode) add(v any) {
+	n.count++
+
+	switch x := v.(type) {
+	case nil:
+		n.types |= typeNull
+	case bool:
+		n.types |= typeBoolean
+	case float64:
+		n.addNumber(x)
+	case json.Number:
+		if f, err := x.Float64(); err == nil {
+			n.addNumber(f)
+		} else {
+			n.types |= typeNumber
+		}
+	case int, int64, int32:
+		n.types |= typeInteger
+	case string:
+		n.addString(x)
+	case M:
+		n.addObject(x)
+	case map[string]any:
+		n.addObject(x)
+	case A:
+		n.addArray(x)
+	case []any:
+		n.addArray(x)
+	}
+}
+
+func (n *schemaNode) addNumber(f float64) {
+	if f == float64(int64(f)) {
+		n.types |= typeInteger
+	} else {
+		n.types |= typeNumber
+	}
+}
+
+func (n *schemaNode) addString(s string) {
+	n.types |= typeString
+
+	if n.strings == 0 {
+		n.formats = formatsOf(s)
+	} else if n.formats != 0 {
+		n.formats &= formatsOf(s)
+	}
+	n.strings++
+
+	if n.tooMany {
+		return
+	}
+	if n.values == nil {
+		n.values = make(map[string]bool)
+	}
+	n.values[s] = true
+	if len(n.values) > maxEnumValues {
+		n.values, n.tooMany = nil, true
+	}
+}
+
+func (n *schemaNode) addObject(m map[string]any) {
+	n.types |= typeObject
+	n.objects++
+
+	if n.properties == nil {
+		n.properties = make(map[string]*schemaNode)
+	}
+	for k, v := range m {
+		p := n.properties[k]
+		if p == nil {
+			p = new(schemaNode)
+			n.properties[k] = p
+		}
+		p.add(v)
+	}
+}
+
+func (n *schemaNode) addArray(a []any) {
+	n.types |= typeArray
+
+	if n.items == nil && len(a) > 0 {
+		n.items = new(schemaNode)
+	}
+	for _, v := range a {
+		n.items.add(v)
+	}
+}
+
+func (n *schemaNode) schema() M {
+	schema := M{}
+
+	types := n.types
+	if types&typeNumber != 0 {
+		types &^= typeInteger
+	}
+	var names A
+	for _, tn := range schemaTypeNames {
+		if types&tn.t != 0 {
+			names = append(names, tn.name)
+		}
+	}
+	switch len(names) {
+	case 0:
+	case 1:
+		schema["type"] = names[0]
+	default:
+		schema["type"] = names
+	}
+
+	if n.types&typeObject != 0 {
+		properties := M{}
+		var required A
+		keys := make([]string, 0, len(n.properties))
+		for k := range n.properties {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			p := n.properties[k]
+			properties[k] = p.schema()
+			if p.count == n.objects {
+				required = append(required, k)
+			}
+		}
+		schema["properties"] = properties
+		if len(required) > 0 {
+			schema["required"] = required
+		}
+	}
+
+	if n.items != nil {
+		schema["items"] = n.items.schema()
+	}
+
+	if n.types&typeString != 0 {
+		for _, fn := range stringFormatNames {
+			if n.formats&fn.f != 0 {
+				schema["format"] = fn.name
+				break
+			}
+		}
+
+		if _, ok := schema["format"]; !ok && n.types&^typeNull == typeString &&
+			n.values != nil && n.strings > len(n.values) {
+			values := make([]string, 0, len(n.values))
+			for s := range n.values {
+				values = append(values, s)
+			}
+			sort.Strings(values)
+
+			enum := make(A, 0, len(values)+1)
+			for _, s := range values {
+				enum = append(enum, s)
+			}
+			if n.types&typeNull != 0 {
+				enum = append(enum, nil)
+			}
+			schema["enum"] = enum
+		}
+	}
+
+	return schema
+}
@@ -0,0 +1,81 @@
+package typed
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestInferSchema(t *testing.T) {
+	t.Parallel()
+
+	var samples []any
+	for _, j := range []string{
+		`{
+			"id": "7c9e6679-7425-40de-944b-e07fc1f90ae7",
+			"status": "paid",
+			"total": 12.5,
+			"items": [{"sku": "A1", "qty": 1}],
+			"email": "wednesday@example.com",
+			"created": "2023-08-17T17:37:08Z",
+			"note": null
+		}`,
+		`{
+			"id": "16fd2706-8baf-433b-82eb-8c7fada847da",
+			"status": "pending",
+			"total": 3,
+			"items": [{"sku": "B2", "qty": 2, "gift": true}],
+			"email": "pugsley@example.com",
+			"created": "2023-08-18T09:00:00+02:00",
+			"note": "leave at the door"
+		}`,
+		`{
+			"id": "6ba7b810-9dad-11d1-80b4-00c04fd430c8",
+			"status": "paid",
+			"total": 8,
+			"items": [],
+			"email": "gomez@example.com",
+			"created": "2023-08-19T12:30:00Z"
+		}`,
+	} {
+		var m M
+		if err := json.Unmarshal([]byte(j), &m); err != nil {
+			t.Fatal(err)
+		}
+		samples = append(samples, m)
+	}
+
+	schema := InferSchema(samples...)
+
+	equal(t, SchemaDialect, schema.StringValue("$schema"))
+	equal(t, "object", schema.StringValue("type"))
+	equalSlice(t, []string{"created", "email", "id", "items", "status", "total"}, schema.Array("required").Strings())
+
+	equal(t, "uuid", schema.StringValue("properties.id.format"))
+	equal(t, "email", schema.StringValue("properties.email.format"))
+	equal(t, "date-time", schema.StringValue("properties.created.format"))
+	equal(t, "number", schema.StringValue("properties.total.type"))
+	equalSlice(t, []string{"paid", "pending"}, schema.Array("properties.status.enum").Strings())
+	equalSlice(t, []string{"string", "null"}, schema.Array("properties.note.type").Strings())
+	equal(t, false, schema.Exists("properties.note.enum"))
+
+	equal(t, "array", schema.StringValue("properties.items.type"))
+	equal(t, "integer", schema.StringValue("properties.items.items.properties.qty.type"))
+	equal(t, "boolean", schema.StringValue("properties.items.items.properties.gift.type"))
+	equalSlice(t, []string{"qty", "sku"}, schema.Array("properties.items.items.required").Strings())
+}
+
+func TestInferSchema_Empty(t *testing.T) {
+	t.Parallel()
+
+	schema := InferSchema()
+	equalSlice(t, []string{"$schema"}, schema.Keys())
+}
+
+func TestInferSchema_Unwrapped(t *testing.T) {
+	t.Parallel()
+
+	schema := InferSchema(map[string]any{"tags": []any{"a", "b"}}, []any{float64(1)})
+	equalSlice(t, []string{"array", "object"}, schema.Array("type").Strings())
+	equal(t, "string", schema.StringValue("properties.tags.items.type"))
+	equal(t, "integer", schema.StringValue("items.type"))
+}
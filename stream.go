@@ -0,0 +1,244 @@
+package typed
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"strconv"
+)
+
+// Elements returns an iterator over the elements of a JSON array read from the
+// input, decoding one element at a time, so arrays larger than memory can be
+// processed. Objects are decoded as M and arrays as A. The limits of the Decoder
+// apply to every element.
+//
+// If path is empty, the array is the next value of the input. Otherwise it is the value
+// for path, in the syntax of the accessors, within the next value of the input, such as
+// "data.items" within an envelope; the values preceding it are skipped without being
+// decoded, and the values following it are not read. As the input is read once,
+// negative indexes and slices are not supported, and are reported as errors; keys
+// written like them must be quoted, as in `data["-1"]`.
+//
+// The iteration stops after the first error, which is yielded with a nil value.
+func (d *Decoder) Elements(path string) iter.Seq2[any, error] {
+	return func(yield func(any, error) bool) {
+		d.path = d.path[:0]
+		if err := d.find(path); err != nil {
+			yield(nil, err)
+			return
+		}
+
+		tok, err := d.dec.Token()
+		if err != nil {
+			yield(nil, d.error(err))
+			return
+		}
+		if tok != json.Delim('[') {
+			yield(nil, d.error(fmt.Errorf("%s is not an array", kindOf(tok))))
+			return
+		}
+
+		depth := len(d.path) + 1
+		for i := 0; d.dec.More(); i++ {
+			if d.maxArrayLen > 0 && i == d.maxArrayLen {
+				yield(nil, d.error(ErrMaxArrayLen))
+				return
+			}
+
+			tok, err := d.dec.Token()
+			if err != nil {
+				yield(nil, d.error(err))
+				return
+			}
+			d.path = append(d.path, strconv.Itoa(i))
+			v, err := d.value(tok, depth, false)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			d.path = d.path[:len(d.path)-1]
+
+			if !yield(v, nil) {
+				return
+			}
+		}
+		if _, err := d.dec.Token(); err != nil {
+			yield(nil, d.error(err))
+		}
+	}
+}
+
+// find advances the input to the value for path, leaving d.path set to it.
+func (d *Decoder) find(path string) error {
+	if path == "" {
+		return nil
+	}
+
+	segs, err := segments(path)
+	if err != nil {
+		return err
+	}
+	for _, seg := range segs {
+		if seg.slice || seg.ok && seg.index < 0 {
+			return fmt.Errorf("typed: %q in path %q: negative indexes and slices are unsupported by Elements", seg.key, path)
+		}
+	}
+
+	for _, seg := range segs {
+		tok, err := d.dec.Token()
+		if err != nil {
+			return d.error(err)
+		}
+
+		found := false
+		switch tok {
+		case json.Delim('{'):
+			for !found && d.dec.More() {
+				tok, err := d.dec.Token()
+				if err != nil {
+					return d.error(err)
+				}
+				if found = tok.(string) == seg.key; !found {
+					if err := d.skip(); err != nil {
+						return err
+					}
+				}
+			}
+		case json.Delim('['):
+			if !seg.ok {
+				_, err := strconv.Atoi(seg.key)
+				return d.error(err)
+			}
+			for j := 0; !found && d.dec.More(); j++ {
+				if found = j == seg.index; !found {
+					if err := d.skip(); err != nil {
+						return err
+					}
+				}
+			}
+		default:
+			return d.error(fmt.Errorf("unknown type %s", kindOf(tok)))
+		}
+
+		d.path = append(d.path, EscapeKey(seg.key))
+		if !found {
+			return d.error(errors.New("not found"))
+		}
+	}
+	return nil
+}
+
+// skip skips the next value of the input.
+func (d *Decoder) skip() error {
+	depth := 0
+	for {
+		tok, err := d.dec.Token()
+		if err != nil {
+			return d.error(err)
+		}
+		switch tok {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+		if depth == 0 {
+			return nil
+		}
+	}
+}
+
+// A LineError records an error in the line of JSON Lines input or output with the
+// given number, counting from 1.
+type LineError struct {
+	Line int
+	Err  error
+}
+
+func (e *LineError) Error() string {
+	return fmt.Sprintf("typed: line %d: %v", e.Line, e.Err)
+}
+
+func (e *LineError) Unwrap() error {
+	return e.Err
+}
+
+// ReadLines returns an iterator over the documents of JSON Lines (NDJSON) input read
+// from r, one JSON object per line. Blank lines are skipped. Object keys repeated
+// across lines share their storage, as with Parser.InternKeys, which interns a
+// bounded number of keys.
+//
+// The iteration stops after the first error, which is yielded with a nil document. Errors
+// in the input are reported as a *LineError.
+func ReadLines(r io.Reader) iter.Seq2[M, error] {
+	return func(yield func(M, error) bool) {
+		br := bufio.NewReader(r)
+		p := Parser{InternKeys: true}
+		for n := 1; ; n++ {
+			line, err := br.ReadBytes('\n')
+			if len(bytes.TrimSpace(line)) > 0 {
+				v, perr := p.Parse(line)
+				if perr != nil {
+					yield(nil, &LineError{Line: n, Err: perr})
+					return
+				}
+
+				m, ok := v.(M)
+				if !ok {
+					yield(nil, &LineError{Line: n, Err: fmt.Errorf("%s is not an object", kindOf(v))})
+					return
+				}
+				if !yield(m, nil) {
+					return
+				}
+			}
+
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			if err != nil {
+				yield(nil, &LineError{Line: n, Err: err})
+				return
+			}
+		}
+	}
+}
+
+// A LineWriter writes documents as JSON Lines (NDJSON), one JSON object per line.
+type LineWriter struct {
+	w    io.Writer
+	line int
+}
+
+// NewLineWriter returns a new LineWriter that writes to w.
+func NewLineWriter(w io.Writer) *LineWriter {
+	return &LineWriter{w: w}
+}
+
+// Write writes m as the next line. Errors are reported as a *LineError.
+func (w *LineWriter) Write(m M) error {
+	w.line++
+
+	b, err := json.Marshal(m)
+	if err != nil {
+		return &LineError{Line: w.line, Err: err}
+	}
+	if _, err := w.w.Write(append(b, '\n')); err != nil {
+		return &LineError{Line: w.line, Err: err}
+	}
+	return nil
+}
+
+// WriteAll writes the documents of seq, stopping at the first error.
+func (w *LineWriter) WriteAll(seq iter.Seq[M]) error {
+	for m := range seq {
+		if err := w.Write(m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package typed
+
+import (
+	"bytes"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestDecoder_Elements(t *testing.T) {
+	t.Parallel()
+
+	dec := NewDecoder(strings.NewReader(`{
+		"meta": {"count": 3, "items": ["not", "these"]},
+		"data": {"cursor": null, "items": [{"id": 1}, [2], 3]},
+		"trailer": {"malformed`))
+
+	var got []any
+	for v, err := range dec.Elements("data.items") {
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, v)
+	}
+
+	equal(t, 3, len(got))
+	equal(t, 1, got[0].(M).AsInt("id"))
+	equalSlice(t, []float64{2}, got[1].(A).Floats())
+	equal(t, 3, got[2].(float64))
+}
+
+func TestDecoder_ElementsRoot(t *testing.T) {
+	t.Parallel()
+
+	dec := NewDecoder(strings.NewReader(`[{"id": 1}, {"id": 2}, {"id": 3}]`))
+
+	var ids []int
+	for v, err := range dec.Elements("") {
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, v.(M).AsInt("id"))
+		if len(ids) == 2 {
+			break
+		}
+	}
+	equalSlice(t, []int{1, 2}, ids)
+}
+
+func TestDecoder_ElementsErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input, path string
+		err         string
+	}{
+		{`{"data": {}}`, "data.items", `typed: key "data.items": not found (offset 10)`},
+		{`{"data": [1]}`, "data.-1", `typed: "-1" in path "data.-1": negative indexes and slices are unsupported by Elements`},
+		{`{"data": [1]}`, "data.0:1", `typed: "0:1" in path "data.0:1": negative indexes and slices are unsupported by Elements`},
+		{`{"data": {"-1": 1}}`, `data["-1"]`, `typed: key "data.-1": number is not an array (offset 17)`},
+		{`{"a.b": {}}`, `a\.b.c`, `typed: key "a\\.b.c": not found (offset 9)`},
+		{`{"data": {"items": {}}}`, "data.items", `typed: key "data.items": object is not an array (offset 20)`},
+		{`{"data": [[1, 2, 3]]}`, "data.0", `typed: key "data.0": exceeds maximum array length (offset 15)`},
+	}
+	for _, tc := range tests {
+		dec := NewDecoder(strings.NewReader(tc.input))
+		dec.SetMaxArrayLen(2)
+
+		var err error
+		for _, err = range dec.Elements(tc.path) {
+			if err != nil {
+				break
+			}
+		}
+		if err == nil || err.Error() != tc.err {
+			t.Errorf("%s: want %s; got %v", tc.input, tc.err, err)
+		}
+	}
+}
+
+func TestReadLines(t *testing.T) {
+	t.Parallel()
+
+	input := "{\"id\": 1}\n\n{\"id\": 2}\r\n{\"id\": 3}"
+	var ids []int
+	for m, err := range ReadLines(strings.NewReader(input)) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, m.AsInt("id"))
+	}
+	equalSlice(t, []int{1, 2, 3}, ids)
+}
+
+func TestReadLines_Error(t *testing.T) {
+	t.Parallel()
+
+	input := "{\"id\": 1}\n[2]\n{\"id\": 3}\n"
+	var err error
+	n := 0
+	for _, err = range ReadLines(strings.NewReader(input)) {
+		if err != nil {
+			break
+		}
+		n++
+	}
+
+	var le *LineError
+	if !errors.As(err, &le) {
+		t.Fatalf("want *LineError; got %v", err)
+	}
+	equal(t, 2, le.Line)
+	equal(t, 1, n)
+}
+
+func TestLineWriter(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	w := NewLineWriter(&buf)
+	if err := w.WriteAll(slices.Values([]M{{"id": 1}, {"id": 2}})); err != nil {
+		t.Fatal(err)
+	}
+	equal(t, "{\"id\":1}\n{\"id\":2}\n", buf.String())
+
+	err := w.Write(M{"bad": func() {}})
+	var le *LineError
+	equal(t, true, errors.As(err, &le))
+	equal(t, 3, le.Line)
+}
@@ -0,0 +1,162 @@
+package typed
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+// Pseudo-layouts of TimeParser matching Unix times, as numbers or numeric strings,
+// in the unit of their names. LayoutUnixAuto guesses the unit from the magnitude of
+// the time, and is reported as the layout of that unit when it matches: seconds
+// below 1e11, milliseconds below 1e14, microseconds below 1e17 and nanoseconds
+// above.
+const (
+	LayoutUnix      = "unix"
+	LayoutUnixMilli = "unixmilli"
+	LayoutUnixMicro = "unixmicro"
+	LayoutUnixNano  = "unixnano"
+	LayoutUnixAuto  = "unixauto"
+)
+
+var unixUnits = map[string]time.Duration{
+	LayoutUnix:      time.Second,
+	LayoutUnixMilli: time.Millisecond,
+	LayoutUnixMicro: time.Microsecond,
+	LayoutUnixNano:  time.Nanosecond,
+}
+
+// A TimeParser parses the values of documents as times.
+type TimeParser struct {
+	// Layouts are tried in order: the layouts of time.Parse for strings, and the
+	// Unix pseudo-layouts for numbers and numeric strings.
+	Layouts []string
+
+	// Location is the location of the times without a time zone, and of Unix times.
+	// UTC is used if it is nil.
+	Location *time.Location
+}
+
+// defaultTimeParser is the TimeParser of the AsTime accessors and A.Times, which
+// accept RFC 3339 strings only. It is never modified; other parsers are passed to
+// AsTimeWith and A.TimesWith.
+var defaultTimeParser = &TimeParser{Layouts: []string{time.RFC3339}}
+
+// Parse returns the time v represents, and the layout that matched it.
+func (p *TimeParser) Parse(v any) (time.Time, string, error) {
+	loc := p.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	var (
+		s                 string
+		f                 float64
+		isFloat, isNumber bool
+	)
+	switch x := v.(type) {
+	case string:
+		s = x
+	case float64:
+		f, isFloat, isNumber = x, true, true
+	case json.Number:
+		s = string(x)
+		if _, err := x.Float64(); err == nil {
+			isNumber = true
+		}
+	default:
+		_, err := convert[string](v)
+		return time.Time{}, "", err
+	}
+
+	var err error
+	for _, layout := range p.Layouts {
+		if _, ok := unixUnits[layout]; ok || layout == LayoutUnixAuto {
+			var t time.Time
+			if isFloat {
+				t, layout, err = unixFloat(f, layout)
+			} else {
+				t, layout, err = unixString(s, layout)
+			}
+			if err == nil {
+				return t.In(loc), layout, nil
+			}
+			continue
+		}
+		if isNumber {
+			err = fmt.Errorf("cannot parse number %v as layout %q", v, layout)
+			continue
+		}
+
+		var t time.Time
+		if t, err = time.ParseInLocation(layout, s, loc); err == nil {
+			return t, layout, nil
+		}
+	}
+
+	switch {
+	case len(p.Layouts) == 0:
+		err = fmt.Errorf("no layout to parse %v as time", v)
+	case len(p.Layouts) > 1:
+		err = fmt.Errorf("no layout matches %v", v)
+	}
+	return time.Time{}, "", err
+}
+
+// parse parses v, the result of a lookup failing with err.
+func (p *TimeParser) parse(v any, err error) (time.Time, string, error) {
+	if err != nil {
+		return time.Time{}, "", err
+	}
+	return p.Parse(v)
+}
+
+// unixString returns the Unix time s represents in the unit of layout.
+func unixString(s, layout string) (time.Time, string, error) {
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		if layout == LayoutUnixAuto {
+			layout = unixLayoutOf(float64(n))
+		}
+		switch layout {
+		case LayoutUnix:
+			return time.Unix(n, 0), layout, nil
+		case LayoutUnixMilli:
+			return time.UnixMilli(n), layout, nil
+		case LayoutUnixMicro:
+			return time.UnixMicro(n), layout, nil
+		}
+		return time.Unix(0, n), layout, nil
+	}
+
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return time.Time{}, "", fmt.Errorf("cannot parse %q as Unix time", s)
+	}
+	return unixFloat(f, layout)
+}
+
+// unixFloat returns the Unix time f represents in the unit of layout.
+func unixFloat(f float64, layout string) (time.Time, string, error) {
+	if layout == LayoutUnixAuto {
+		layout = unixLayoutOf(f)
+	}
+	sec, frac := math.Modf(f * float64(unixUnits[layout]) / float64(time.Second))
+	if math.IsNaN(sec) || math.Abs(sec) >= 1<<62 {
+		return time.Time{}, "", fmt.Errorf("Unix time %v out of range", f)
+	}
+	return time.Unix(int64(sec), int64(math.Round(frac*float64(time.Second)))), layout, nil
+}
+
+func unixLayoutOf(f float64) string {
+	switch f = math.Abs(f); {
+	case f < 1e11:
+		return LayoutUnix
+	case f < 1e14:
+		return LayoutUnixMilli
+	case f < 1e17:
+		return LayoutUnixMicro
+	}
+	return LayoutUnixNano
+}
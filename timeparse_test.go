@@ -0,0 +1,96 @@
+package typed
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestTimeParser(t *testing.T) {
+	t.Parallel()
+
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip(err)
+	}
+	p := &TimeParser{
+		Layouts:  []string{time.RFC3339Nano, time.DateOnly, time.RFC1123, LayoutUnixAuto},
+		Location: berlin,
+	}
+	want := time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		v      any
+		want   time.Time
+		layout string
+	}{
+		{"2024-03-01T12:30:00Z", want, time.RFC3339Nano},
+		{"2024-03-01", time.Date(2024, 3, 1, 0, 0, 0, 0, berlin), time.DateOnly},
+		{"Fri, 01 Mar 2024 12:30:00 UTC", want, time.RFC1123},
+		{float64(want.Unix()), want, LayoutUnix},
+		{json.Number("1709296200000"), want, LayoutUnixMilli},
+		{"1709296200000000", want, LayoutUnixMicro},
+		{"1709296200000000000", want, LayoutUnixNano},
+		{"1709296200.5", want.Add(500 * time.Millisecond), LayoutUnix},
+	}
+	for _, tc := range tests {
+		got, layout, err := p.Parse(tc.v)
+		if err != nil {
+			t.Errorf("%v: %v", tc.v, err)
+			continue
+		}
+		equal(t, true, tc.want.Equal(got))
+		equal(t, tc.layout, layout)
+	}
+
+	got, _, err := p.Parse(1.7092962e9)
+	equal(t, nil, err)
+	equal(t, berlin, got.Location())
+
+	for _, v := range []any{"yesterday", true, nil, M{}} {
+		_, _, err := p.Parse(v)
+		equal(t, true, err != nil)
+	}
+	_, _, err = (&TimeParser{Layouts: []string{time.DateOnly}}).Parse(20240301.0)
+	equal(t, true, err != nil)
+	_, _, err = (&TimeParser{Layouts: []string{LayoutUnixMilli}}).Parse("")
+	equal(t, true, err != nil)
+}
+
+func TestAsTimeWith(t *testing.T) {
+	t.Parallel()
+
+	p := &TimeParser{Layouts: []string{time.DateOnly, LayoutUnixMilli}}
+	m := M{"day": "2024-03-01", "at": 1709296200000.0, "ts": A{"2024-03-01T12:30:00Z", "2024-03-02T00:00:00+01:00"}}
+
+	tm, layout, err := m.AsTimeWith("day", p)
+	equal(t, nil, err)
+	equal(t, time.DateOnly, layout)
+	equal(t, 1, tm.Day())
+
+	tm, layout, err = D{{"at", 1709296200000.0}}.AsTimeWith("at", p)
+	equal(t, nil, err)
+	equal(t, LayoutUnixMilli, layout)
+	equal(t, 12, tm.Hour())
+
+	_, _, err = m.AsTimeWith("missing", p)
+	equal(t, `not found key "missing"`, err.Error())
+
+	_, ok := m.AsTimeOK("at")
+	equal(t, false, ok)
+
+	ts := m.Array("ts").Times()
+	equal(t, 2, len(ts))
+	equal(t, true, ts[1].Equal(time.Date(2024, 3, 1, 23, 0, 0, 0, time.UTC)))
+	_, err = A{"2024-03-01T12:30:00Z", 1.0}.TimesWith(defaultTimeParser)
+	equal(t, `element 1: cannot parse number 1 as layout "2006-01-02T15:04:05Z07:00"`, err.Error())
+	_, ok = A{"2024-03-01T12:30:00Z", "2024-03-01"}.TimesOK()
+	equal(t, false, ok)
+	equal(t, true, panics(func() { A{1.0}.Times() }))
+
+	ts, err = A{"2024-03-01", 1709296200000.0}.TimesWith(p)
+	equal(t, nil, err)
+	equal(t, 12, ts[1].Hour())
+	_, err = A{"2024-03-01", "soon"}.TimesWith(p)
+	equal(t, `element 1: no layout matches soon`, err.Error())
+}
@@ -0,0 +1,318 @@
+package typed
+
+import (
+	"cmp"
+	"encoding/json"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// An Action tells Transform what to do with a value.
+type Action int
+
+const (
+	// Descend keeps the value, transforming the values within it if it is a document
+	// or an array.
+	Descend Action = iota
+
+	// Replace replaces the value with the one returned, which is not transformed
+	// further.
+	Replace
+
+	// Delete removes the value from its document or array.
+	Delete
+)
+
+// TransformFunc is the type of the function called by Transform for each value. The
+// path is the path of the value within the document transformed, in the syntax of the
+// accessors, with its keys escaped; it is empty for the document itself. The
+// returned value is used for Replace only.
+type TransformFunc func(path Path, value any) (any, Action)
+
+// Transform returns a new document made by calling fn for doc and, depth-first, for
+// the values within it, as fn asks. Documents and arrays are copied, as M, D and A,
+// so doc is not modified; Transform returns nil if fn deletes doc itself.
+func Transform(doc any, fn TransformFunc) any {
+	v, ok := transform("", doc, fn)
+	if !ok {
+		return nil
+	}
+	return v
+}
+
+// transform returns the transformed v, and false if it is deleted.
+func transform(path Path, v any, fn TransformFunc) (any, bool) {
+	r, action := fn(path, v)
+	switch action {
+	case Replace:
+		return r, true
+	case Delete:
+		return nil, false
+	}
+	return transformChildren(path, v, fn), true
+}
+
+// transformChildren returns a copy of v with the values within it transformed, if it
+// is a document or an array, or v itself.
+func transformChildren(path Path, v any, fn TransformFunc) any {
+	switch x := v.(type) {
+	case M:
+		return transformM(path, x, fn)
+	case map[string]any:
+		return transformM(path, x, fn)
+	case D:
+		d := make(D, 0, len(x))
+		for _, e := range x {
+			if v, ok := transform(childPath(path, EscapeKey(e.Key)), e.Value, fn); ok {
+				d = append(d, E{e.Key, v})
+			}
+		}
+		return d
+	case A:
+		return transformA(path, x, fn)
+	case []any:
+		return transformA(path, x, fn)
+	}
+	return v
+}
+
+func transformM(path Path, m map[string]any, fn TransformFunc) M {
+	out := make(M, len(m))
+	for k, v := range m {
+		if v, ok := transform(childPath(path, EscapeKey(k)), v, fn); ok {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+func transformA(path Path, a []any, fn TransformFunc) A {
+	out := make(A, 0, len(a))
+	for i, v := range a {
+		if v, ok := transform(childPath(path, strconv.Itoa(i)), v, fn); ok {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func childPath(path Path, key string) Path {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// DropNulls is a TransformFunc deleting nulls.
+func DropNulls(path Path, value any) (any, Action) {
+	if value == nil {
+		return nil, Delete
+	}
+	return nil, Descend
+}
+
+// DropEmpty is a TransformFunc deleting empty documents and arrays, including those
+// left empty by deleting their own empty children. The document transformed itself
+// is kept.
+func DropEmpty(path Path, value any) (any, Action) {
+	switch KindOf(value) {
+	case KindDocument, KindArray:
+	default:
+		return nil, Descend
+	}
+
+	v := transformChildren(path, value, DropEmpty)
+	var n int
+	switch x := v.(type) {
+	case M:
+		n = len(x)
+	case D:
+		n = len(x)
+	case A:
+		n = len(x)
+	}
+	if n == 0 && path != "" {
+		return nil, Delete
+	}
+	return v, Replace
+}
+
+// TrimStrings is a TransformFunc removing the leading and trailing white space of
+// strings.
+func TrimStrings(path Path, value any) (any, Action) {
+	if s, ok := value.(string); ok {
+		return strings.TrimSpace(s), Replace
+	}
+	return nil, Descend
+}
+
+// SnakeCaseKeys is a TransformFunc converting the keys of documents to snake_case,
+// such as "user_id" for "userID". If keys collide once converted, the value of the
+// last one in sorted order, or in document order for D, is kept.
+func SnakeCaseKeys(path Path, value any) (any, Action) {
+	return renameKeys(path, value, SnakeCaseKeys, func(words []string) string {
+		return strings.ToLower(strings.Join(words, "_"))
+	})
+}
+
+// KebabCaseKeys is a TransformFunc converting the keys of documents to kebab-case,
+// such as "user-id" for "userID". Colliding keys are handled as by SnakeCaseKeys.
+func KebabCaseKeys(path Path, value any) (any, Action) {
+	return renameKeys(path, value, KebabCaseKeys, func(words []string) string {
+		return strings.ToLower(strings.Join(words, "-"))
+	})
+}
+
+// CamelCaseKeys is a TransformFunc converting the keys of documents to camelCase,
+// such as "userId" for "user_id". Colliding keys are handled as by SnakeCaseKeys.
+func CamelCaseKeys(path Path, value any) (any, Action) {
+	return renameKeys(path, value, CamelCaseKeys, func(words []string) string {
+		var b strings.Builder
+		for i, w := range words {
+			w = strings.ToLower(w)
+			if i > 0 {
+				r, size := utf8.DecodeRuneInString(w)
+				b.WriteRune(unicode.ToUpper(r))
+				w = w[size:]
+			}
+			b.WriteString(w)
+		}
+		return b.String()
+	})
+}
+
+// renameKeys returns value with the keys of the documents within it converted by
+// joining their words with join.
+func renameKeys(path Path, value any, fn TransformFunc, join func([]string) string) (any, Action) {
+	switch x := value.(type) {
+	case M:
+		return renameM(path, x, fn, join), Replace
+	case map[string]any:
+		return renameM(path, x, fn, join), Replace
+	case D:
+		d := make(D, 0, len(x))
+		for _, e := range x {
+			v, ok := transform(childPath(path, EscapeKey(e.Key)), e.Value, fn)
+			if !ok {
+				continue
+			}
+			k := convertKey(e.Key, join)
+			if i := d.index(k); i >= 0 {
+				d[i].Value = v
+				continue
+			}
+			d = append(d, E{k, v})
+		}
+		return d, Replace
+	}
+	return nil, Descend
+}
+
+func renameM(path Path, m map[string]any, fn TransformFunc, join func([]string) string) M {
+	out := make(M, len(m))
+	for _, k := range M(m).Keys() {
+		if v, ok := transform(childPath(path, EscapeKey(k)), m[k], fn); ok {
+			out[convertKey(k, join)] = v
+		}
+	}
+	return out
+}
+
+// convertKey returns k with its words joined by join, or k itself if it holds no
+// words.
+func convertKey(k string, join func([]string) string) string {
+	w := words(k)
+	if len(w) == 0 {
+		return k
+	}
+	return join(w)
+}
+
+// words splits s into its words, at underscores, hyphens, spaces and dots, and at
+// changes of case, such as "HTTP", "Server" and "ID" for "HTTPServerID".
+func words(s string) []string {
+	var (
+		words []string
+		start = -1
+	)
+	runes := []rune(s)
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if start >= 0 {
+				words = append(words, string(runes[start:i]))
+				start = -1
+			}
+			continue
+		}
+		if start < 0 {
+			start = i
+			continue
+		}
+
+		prev := runes[i-1]
+		upper := unicode.IsUpper(r)
+		if upper && (unicode.IsLower(prev) || unicode.IsDigit(prev)) ||
+			// The last upper case letter of an acronym starts the next word.
+			upper && unicode.IsUpper(prev) && i+1 < len(runes) && unicode.IsLower(runes[i+1]) {
+			words = append(words, string(runes[start:i]))
+			start = i
+		}
+	}
+	if start >= 0 {
+		words = append(words, string(runes[start:]))
+	}
+	return words
+}
+
+// SortScalars is a TransformFunc sorting the arrays holding only booleans, numbers,
+// strings and nulls. Values of different kinds are ordered by kind: nulls first,
+// then booleans, numbers and strings.
+func SortScalars(path Path, value any) (any, Action) {
+	var a []any
+	switch x := value.(type) {
+	case A:
+		a = x
+	case []any:
+		a = x
+	default:
+		return nil, Descend
+	}
+	for _, v := range a {
+		switch KindOf(v) {
+		case KindDocument, KindArray, KindInvalid:
+			return nil, Descend
+		}
+	}
+
+	sorted := slices.Clone(A(a))
+	slices.SortStableFunc(sorted, compareScalars)
+	return sorted, Replace
+}
+
+func compareScalars(a, b any) int {
+	if c := cmp.Compare(KindOf(a), KindOf(b)); c != 0 {
+		return c
+	}
+
+	switch x := a.(type) {
+	case bool:
+		y := b.(bool)
+		switch {
+		case x == y:
+			return 0
+		case !x:
+			return -1
+		}
+		return 1
+	case string:
+		return strings.Compare(x, b.(string))
+	case float64, json.Number:
+		f, _ := toFloat(a)
+		g, _ := toFloat(b)
+		return cmp.Compare(f, g)
+	}
+	return 0
+}
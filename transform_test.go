@@ -0,0 +1,97 @@
+package typed
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestTransform(t *testing.T) {
+	t.Parallel()
+
+	doc := M{
+		"name":  "Wednesday",
+		"age":   6.0,
+		"debug": M{"trace": "x"},
+		"tags":  A{"a", "secret", "b"},
+	}
+
+	var paths []string
+	got := Transform(doc, func(path Path, value any) (any, Action) {
+		paths = append(paths, path)
+		switch {
+		case path == "debug":
+			return nil, Delete
+		case value == "secret":
+			return nil, Delete
+		case path == "age":
+			return value.(float64) + 1, Replace
+		}
+		return nil, Descend
+	}).(M)
+
+	equal(t, 7.0, got.Float("age"))
+	equal(t, false, got.Exists("debug"))
+	equalSlice(t, A{"a", "b"}, got.Array("tags"))
+	equal(t, 6.0, doc.Float("age"))
+	equal(t, 3, len(doc.Array("tags")))
+	equal(t, 8, len(paths))
+
+	equal(t, nil, Transform(doc, func(Path, any) (any, Action) { return nil, Delete }))
+}
+
+func TestTransform_BuiltIns(t *testing.T) {
+	t.Parallel()
+
+	var doc M
+	err := json.Unmarshal([]byte(`{
+		"userID": " 42 ",
+		"HTTPServer": {"maxConns": 10, "tls_cert": null, "extra": {"a": [], "b": {}}},
+		"ship-to": [{"zip_code": "123"}, null],
+		"tags": ["b", 3, "a", true, null, 1]
+	}`), &doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v := Transform(doc, DropNulls)
+	v = Transform(v, DropEmpty)
+	v = Transform(v, TrimStrings)
+	v = Transform(v, SortScalars)
+	snake := Transform(v, SnakeCaseKeys).(M)
+
+	b, err := json.Marshal(snake)
+	if err != nil {
+		t.Fatal(err)
+	}
+	equal(t, `{"http_server":{"max_conns":10},"ship_to":[{"zip_code":"123"}],"tags":[true,1,3,"a","b"],"user_id":"42"}`, string(b))
+
+	camel := Transform(snake, CamelCaseKeys).(M)
+	equal(t, true, camel.Exists("httpServer.maxConns"))
+	equal(t, "123", camel.StringValue("shipTo.0.zipCode"))
+
+	kebab := Transform(D{{"userID", 1.0}, {"user_id", 2.0}, {"__", 3.0}}, KebabCaseKeys).(D)
+	equalSlice(t, []string{"user-id", "__"}, kebab.Keys())
+	equal(t, 2, kebab.AsInt("user-id"))
+
+	b, err = json.Marshal(Transform(D{{"a", D{}}}, SnakeCaseKeys))
+	if err != nil {
+		t.Fatal(err)
+	}
+	equal(t, `{"a":{}}`, string(b))
+}
+
+func TestWords(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string][]string{
+		"userID":       {"user", "ID"},
+		"HTTPServerID": {"HTTP", "Server", "ID"},
+		"zip_code":     {"zip", "code"},
+		"ship-to.city": {"ship", "to", "city"},
+		"v2Beta":       {"v2", "Beta"},
+		"":             nil,
+	}
+	for s, want := range tests {
+		equalSlice(t, want, words(s))
+	}
+}
@@ -4,15 +4,17 @@
 // Specifically, M and A wrapped map[string]any and []any recurse down.
 // If the given key is multiple keys conjunction with dot "." character,
 // corresponding methods also recurse down.
+//
+// D is an ordered variant of M which preserves the key order of its source.
 package typed
 
 import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"reflect"
 	"sort"
 	"strconv"
-	"strings"
 	"time"
 
 	"golang.org/x/exp/constraints"
@@ -29,12 +31,17 @@ func (m *M) UnmarshalJSON(data []byte) error {
 		return nil
 	}
 
-	var mm map[string]any
-	if err := json.Unmarshal(data, &mm); err != nil {
+	var p Parser
+	v, err := p.Parse(data)
+	if err != nil {
 		return err
 	}
 
-	*m = wrapper(mm).(M)
+	mm, ok := v.(M)
+	if !ok {
+		return &json.UnmarshalTypeError{Value: kindOf(v), Type: reflect.TypeOf(*m)}
+	}
+	*m = mm
 	return nil
 }
 
@@ -47,12 +54,17 @@ func (a *A) UnmarshalJSON(data []byte) error {
 		return nil
 	}
 
-	var aa []any
-	if err := json.Unmarshal(data, &aa); err != nil {
+	var p Parser
+	v, err := p.Parse(data)
+	if err != nil {
 		return err
 	}
 
-	*a = wrapper(aa).(A)
+	aa, ok := v.(A)
+	if !ok {
+		return &json.UnmarshalTypeError{Value: kindOf(v), Type: reflect.TypeOf(*a)}
+	}
+	*a = aa
 	return nil
 }
 
@@ -179,13 +191,11 @@ func (m M) StringValueOK(key string) (string, bool) {
 	return lookupOK[string](m, key)
 }
 
-// AsTime returns the time.Time value the value represents for given key. It panics if the
-// value not represents time.AsTime.
+// AsTime returns the time.Time value the value represents for given key, an RFC 3339
+// string. It panics if the value can not be parsed; AsTimeWith accepts other formats.
 func (m M) AsTime(key string) time.Time {
-	s := lookup[string](m, key)
-
-	var t time.Time
-	if err := t.UnmarshalJSON([]byte(strconv.Quote(s))); err != nil {
+	t, err := asTimeErr(m, key)
+	if err != nil {
 		panic(err)
 	}
 	return t
@@ -194,23 +204,20 @@ func (m M) AsTime(key string) time.Time {
 // AsTimeOK is the same as AsTime, except it returns a boolean instead of
 // panicking.
 func (m M) AsTimeOK(key string) (time.Time, bool) {
-	s, ok := lookupOK[string](m, key)
-	if !ok {
-		return time.Time{}, false
-	}
-
-	var t time.Time
-	err := t.UnmarshalJSON([]byte(strconv.Quote(s)))
+	t, err := asTimeErr(m, key)
 	return t, err == nil
 }
 
-// AsDuration returns the time.Duration value the value represents for given key. It panics if the
-// value can not parsed by time.ParseDuration.
-func (m M) AsDuration(key string) time.Duration {
-	s := lookup[string](m, key)
+// AsTimeWith returns the time.Time value the value represents for given key as
+// parsed by p, and the layout that matched it.
+func (m M) AsTimeWith(key string, p *TimeParser) (time.Time, string, error) {
+	return p.parse(lookupErr[any](m, key))
+}
 
-	var d time.Duration
-	d, err := time.ParseDuration(s)
+// AsDuration returns the time.Duration value the value represents for given key, a string
+// ParseDuration accepts or a number of seconds. It panics if the value can not be parsed.
+func (m M) AsDuration(key string) time.Duration {
+	d, err := asDurationErr(m, key)
 	if err != nil {
 		panic(err)
 	}
@@ -220,15 +227,16 @@ func (m M) AsDuration(key string) time.Duration {
 // AsDurationOK is the same as AsDuration, except it returns a boolean instead of
 // panicking.
 func (m M) AsDurationOK(key string) (time.Duration, bool) {
-	s, ok := lookupOK[string](m, key)
-	if !ok {
-		return 0, false
-	}
-
-	d, err := time.ParseDuration(s)
+	d, err := asDurationErr(m, key)
 	return d, err == nil
 }
 
+// AsDurationWith returns the time.Duration value the value represents for given key as
+// parsed by p.
+func (m M) AsDurationWith(key string, p *DurationParser) (time.Duration, error) {
+	return p.parse(lookupErr[any](m, key))
+}
+
 // Array returns the JSON array the value represents for given key. It panics if the
 // value is a JSON type other than array.
 func (m M) Array(key string) A {
@@ -298,6 +306,33 @@ func (m M) AnyOK(key string) (any, bool) {
 	return unwrapper(a), ok
 }
 
+// Set sets the value for given key, creating intermediate documents as needed. If
+// there are multiple keys concatenated with ".", this method will recurse down, as long as the
+// intermediate nodes are either documents or arrays. Maps and slices within value are wrapped
+// as Wrap does. It panics if an intermediate node is a JSON type other than document or array,
+// or if an array index is out of range.
+func (m M) Set(key string, value any) {
+	if _, err := setErr(m, key, value); err != nil {
+		panic(err)
+	}
+}
+
+// SetOK is the same as Set, except it returns a boolean instead of
+// panicking.
+func (m M) SetOK(key string, value any) bool {
+	_, err := setErr(m, key, value)
+	return err == nil
+}
+
+// Delete removes the value for given key, potentially recursively. If the value is an
+// array element, the following elements are moved down; if it is a slice of an array, all its
+// elements are removed. If the value doesn't exist or key is malformed, Delete is a no-op.
+func (m M) Delete(key string) {
+	if segs, err := segments(key); err == nil {
+		deleteKeys(m, segs)
+	}
+}
+
 // Keys returns all sorted keys within document.
 func (m M) Keys() []string {
 	if m == nil {
@@ -314,6 +349,198 @@ func (m M) Keys() []string {
 	return keys
 }
 
+// The accessors of A take paths starting with the index of an element, such as
+// "1.Name", in the same syntax as the accessors of M.
+
+// Exists reports whether key exists, potentially recursively for the given key. If
+// there are multiple keys concatenated with ".", this method will recurse down, as long as the
+// top and intermediate nodes are either documents or arrays. If an error
+// occurs or if the value doesn't exist, false is returned.
+func (a A) Exists(key string) bool {
+	_, ok := lookupOK[any](a, key)
+	return ok
+}
+
+// IsNumber reports whether the value represents for given key is a JSON number.
+func (a A) IsNumber(key string) bool {
+	_, ok := lookupOK[float64](a, key)
+	return ok
+}
+
+// Bool returns the boolean value the value represents for given key. It panics if the
+// value is a JSON type other than boolean.
+func (a A) Bool(key string) bool {
+	return lookup[bool](a, key)
+}
+
+// BoolOK is the same as Bool, except it returns a boolean instead of
+// panicking.
+func (a A) BoolOK(key string) (bool, bool) {
+	return lookupOK[bool](a, key)
+}
+
+// AsInt returns the int value the value represents for given key. It panics if the
+// value is JSON type other than number.
+func (a A) AsInt(key string) int {
+	return int(lookup[float64](a, key))
+}
+
+// AsIntOK is the same as AsInt, except that it returns a boolean instead of
+// panicking.
+func (a A) AsIntOK(key string) (int, bool) {
+	f, ok := lookupOK[float64](a, key)
+	return int(f), ok
+}
+
+// AsInt64 returns a JSON number as an int64 for given key. It panics if the
+// value type is JSON type other than number.
+func (a A) AsInt64(key string) int64 {
+	return int64(lookup[float64](a, key))
+}
+
+// AsInt64OK is the same as AsInt64, except that it returns a boolean instead of
+// panicking.
+func (a A) AsInt64OK(key string) (int64, bool) {
+	f, ok := lookupOK[float64](a, key)
+	return int64(f), ok
+}
+
+// Float returns the float64 value the value represents for given key. It panics if the
+// value is JSON type other than number.
+func (a A) Float(key string) float64 {
+	return lookup[float64](a, key)
+}
+
+// FloatOK is the same as Float, but returns a boolean instead of panicking.
+func (a A) FloatOK(key string) (float64, bool) {
+	return lookupOK[float64](a, key)
+}
+
+// StringValue returns the string value the value represents for given key. It panics if the
+// value is JSON type other than string.
+func (a A) StringValue(key string) string {
+	return lookup[string](a, key)
+}
+
+// StringValueOK is the same as StringValue, but returns a boolean instead of
+// panicking.
+func (a A) StringValueOK(key string) (string, bool) {
+	return lookupOK[string](a, key)
+}
+
+// AsTime returns the time.Time value the value represents for given key, an RFC 3339
+// string. It panics if the value can not be parsed; AsTimeWith accepts other formats.
+func (a A) AsTime(key string) time.Time {
+	t, err := asTimeErr(a, key)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// AsTimeOK is the same as AsTime, except it returns a boolean instead of
+// panicking.
+func (a A) AsTimeOK(key string) (time.Time, bool) {
+	t, err := asTimeErr(a, key)
+	return t, err == nil
+}
+
+// AsTimeWith returns the time.Time value the value represents for given key as
+// parsed by p, and the layout that matched it.
+func (a A) AsTimeWith(key string, p *TimeParser) (time.Time, string, error) {
+	return p.parse(lookupErr[any](a, key))
+}
+
+// AsDuration returns the time.Duration value the value represents for given key, a string
+// ParseDuration accepts or a number of seconds. It panics if the value can not be parsed.
+func (a A) AsDuration(key string) time.Duration {
+	d, err := asDurationErr(a, key)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// AsDurationOK is the same as AsDuration, except it returns a boolean instead of
+// panicking.
+func (a A) AsDurationOK(key string) (time.Duration, bool) {
+	d, err := asDurationErr(a, key)
+	return d, err == nil
+}
+
+// AsDurationWith returns the time.Duration value the value represents for given key as
+// parsed by p.
+func (a A) AsDurationWith(key string, p *DurationParser) (time.Duration, error) {
+	return p.parse(lookupErr[any](a, key))
+}
+
+// Array returns the JSON array the value represents for given key. It panics if the
+// value is a JSON type other than array.
+func (a A) Array(key string) A {
+	return lookup[A](a, key)
+}
+
+// ArrayOK is the same as Array, except it returns a boolean instead of
+// panicking.
+func (a A) ArrayOK(key string) (A, bool) {
+	return lookupOK[A](a, key)
+}
+
+// Document returns the JSON document the value represents for given key. It panics if the
+// value is a JSON type other than document.
+func (a A) Document(key string) M {
+	return lookup[M](a, key)
+}
+
+// DocumentOK is the same as Document, except it returns a boolean instead of
+// panicking.
+func (a A) DocumentOK(key string) (M, bool) {
+	return lookupOK[M](a, key)
+}
+
+// Map is the same as Document, except it returns a map[string]any
+// instead of M.
+func (a A) Map(key string) map[string]any {
+	return unwrapper(lookup[M](a, key)).(map[string]any)
+}
+
+// MapOK is the same as Map, except it returns a boolean instead of
+// panicking.
+func (a A) MapOK(key string) (map[string]any, bool) {
+	m2, ok := lookupOK[M](a, key)
+	return unwrapper(m2).(map[string]any), ok
+}
+
+// RawMessage returns the raw encoded JSON value the value represents for given key. It returns 'null' if the
+// value doesn't exist.
+func (a A) RawMessage(key string) json.RawMessage {
+	v, ok := lookupOK[any](a, key)
+	if !ok {
+		return nullRawMessage
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	return json.RawMessage(b)
+}
+
+// Any search the array, potentially recursively, for the given key. If
+// there are multiple keys concatenated with ".", this method will recurse down, as long as the
+// top and intermediate nodes are either documents or arrays. If an error
+// occurs or if the value doesn't exist, this method panics.
+func (a A) Any(key string) any {
+	return unwrapper(lookup[any](a, key))
+}
+
+// AnyOK is the same as Any, except it returns a boolean instead of
+// panicking.
+func (a A) AnyOK(key string) (any, bool) {
+	v, ok := lookupOK[any](a, key)
+	return unwrapper(v), ok
+}
+
 // Bools returns the slice of boolean the array represents. It panics if the
 // one of the elements is JSON type other than boolean.
 func (a A) Bools() []bool {
@@ -374,6 +601,69 @@ func (a A) StringsOK() ([]string, bool) {
 	return arrayOK[string](a)
 }
 
+// Times returns the slice of time.Time the array represents, RFC 3339 strings. It
+// panics if one of elements can not be parsed.
+func (a A) Times() []time.Time {
+	ts, err := a.TimesWith(defaultTimeParser)
+	if err != nil {
+		panic(err)
+	}
+	return ts
+}
+
+// TimesOK is the same as Times, except it returns a boolean instead of
+// panicking.
+func (a A) TimesOK() ([]time.Time, bool) {
+	ts, err := a.TimesWith(defaultTimeParser)
+	return ts, err == nil
+}
+
+// Durations returns the slice of time.Duration the array represents, strings
+// ParseDuration accepts or numbers of seconds. It panics if one of elements can not
+// be parsed.
+func (a A) Durations() []time.Duration {
+	ds, err := a.DurationsWith(defaultDurationParser)
+	if err != nil {
+		panic(err)
+	}
+	return ds
+}
+
+// DurationsOK is the same as Durations, except it returns a boolean instead of
+// panicking.
+func (a A) DurationsOK() ([]time.Duration, bool) {
+	ds, err := a.DurationsWith(defaultDurationParser)
+	return ds, err == nil
+}
+
+// TimesWith returns the slice of time.Time the array represents as parsed by p. The
+// error reports the index of the first element which can not be parsed.
+func (a A) TimesWith(p *TimeParser) ([]time.Time, error) {
+	ts := make([]time.Time, len(a))
+	for i, v := range a {
+		t, _, err := p.Parse(v)
+		if err != nil {
+			return nil, fmt.Errorf("element %d: %w", i, err)
+		}
+		ts[i] = t
+	}
+	return ts, nil
+}
+
+// DurationsWith returns the slice of time.Duration the array represents as parsed by
+// p. The error reports the index of the first element which can not be parsed.
+func (a A) DurationsWith(p *DurationParser) ([]time.Duration, error) {
+	ds := make([]time.Duration, len(a))
+	for i, v := range a {
+		d, err := p.Parse(v)
+		if err != nil {
+			return nil, fmt.Errorf("element %d: %w", i, err)
+		}
+		ds[i] = d
+	}
+	return ds, nil
+}
+
 // Documents returns the slice of JSON document the array represents. It panics if one
 // of elements is a JSON type other than document.
 func (a A) Documents() []M {
@@ -419,7 +709,11 @@ func asNumericArray[E constraints.Integer | constraints.Float](a []any) []E {
 
 	s := make([]E, len(a))
 	for i, v := range a {
-		s[i] = E(v.(float64))
+		f, ok := toFloat(v)
+		if !ok {
+			panic(fmt.Errorf("element %d of type %T is not a number", i, v))
+		}
+		s[i] = E(f)
 	}
 	return s
 }
@@ -432,7 +726,7 @@ func asNumericArrayOK[E constraints.Integer | constraints.Float](a []any) (s []E
 	s = make([]E, len(a))
 	var f float64
 	for i, v := range a {
-		f, ok = v.(float64)
+		f, ok = toFloat(v)
 		if !ok {
 			return nil, false
 		}
@@ -468,7 +762,7 @@ func arrayOK[E any](a []any) (s []E, ok bool) {
 	return s, true
 }
 
-func lookup[E any](a any, key string) E {
+func lookup[E any](a any, key Path) E {
 	e, err := lookupErr[E](a, key)
 	if err != nil {
 		panic(err)
@@ -476,74 +770,174 @@ func lookup[E any](a any, key string) E {
 	return e
 }
 
-func lookupOK[E any](a any, key string) (e E, ok bool) {
-	var err error
-	e, err = lookupErr[E](a, key)
-	return e, err == nil
-}
-
-func lookupErr[E any](a any, key string) (e E, err error) {
-	keys := strings.Split(key, ".")
-	for i, k := range keys[:len(keys)-1] {
-		switch x := a.(type) {
-		default:
-			err = fmt.Errorf("unknown type %T", a)
-			return
-		case M:
-			var ok bool
-			a, ok = x[k]
-			if !ok {
-				err = fmt.Errorf("not found key %q", strings.Join(keys[:i+1], "."))
-				return
-			}
-		case A:
-			var j int
-			j, err = strconv.Atoi(k)
-			if err != nil {
-				return
-			}
-			if j < 0 || j >= len(x) {
-				err = fmt.Errorf("not found key %q", strings.Join(keys[:i+1], "."))
-				return
-			}
-			a = x[j]
-		}
+func lookupOK[E any](a any, key Path) (e E, ok bool) {
+	v, _, result := walk(a, key)
+	if result != lookupFound {
+		return e, false
 	}
+	return as[E](v)
+}
+
+func lookupErr[E any](a any, key Path) (e E, err error) {
+	v, n, result := walk(a, key)
+	if result != lookupFound {
+		return e, lookupError(a, key, n, result)
+	}
+	return convert[E](v)
+}
 
-	lastKey := keys[len(keys)-1]
+// toFloat returns the value of the JSON number v.
+func toFloat(v any) (float64, bool) {
+	switch x := v.(type) {
+	case float64:
+		return x, true
+	case json.Number:
+		f, err := x.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func asTimeErr(a any, key string) (time.Time, error) {
+	return parseTime(lookupErr[any](a, key))
+}
+
+func asDurationErr(a any, key string) (time.Duration, error) {
+	return parseDuration(lookupErr[any](a, key))
+}
+
+// parseTime parses v, the result of a lookup failing with err, as an RFC 3339 time.
+func parseTime(v any, err error) (time.Time, error) {
+	t, _, err := defaultTimeParser.parse(v, err)
+	return t, err
+}
+
+// parseDuration parses v, the result of a lookup failing with err, as a duration.
+func parseDuration(v any, err error) (time.Duration, error) {
+	return defaultDurationParser.parse(v, err)
+}
+
+// setErr sets the value for given key within a. It returns a, or the D or A
+// replacing it if it had to grow.
+func setErr(a any, key Path, value any) (any, error) {
+	segs, err := segments(key)
+	if err != nil {
+		return nil, err
+	}
+	return setKeys(a, key, segs, 0, value)
+}
+
+func setKeys(a any, key Path, segs []segment, i int, value any) (any, error) {
+	k, last := segs[i].key, i == len(segs)-1
 	switch x := a.(type) {
 	default:
-		err = fmt.Errorf("unknown type %T", a)
-		return
+		return nil, fmt.Errorf("unknown type %T", a)
 	case M:
-		v, ok := x[lastKey]
-		if !ok {
-			err = fmt.Errorf("not found key %q", key)
-			return
+		if x == nil {
+			prefix := ""
+			if i > 0 {
+				prefix = key[:segs[i-1].end]
+			}
+			return nil, fmt.Errorf("nil document at key %q", prefix)
+		}
+		if last {
+			x[k] = wrapper(value)
+			return x, nil
 		}
 
-		defer func() {
-			if r := recover(); r != nil {
-				err = fmt.Errorf("%v", r)
+		v, ok := x[k]
+		if !ok {
+			v = M{}
+		}
+		v, err := setKeys(v, key, segs, i+1, value)
+		if err != nil {
+			return nil, err
+		}
+		x[k] = v
+		return x, nil
+	case D:
+		j := x.index(k)
+		if last {
+			if j < 0 {
+				return append(x, E{k, wrapper(value)}), nil
 			}
-		}()
-		return v.(E), nil
-	case A:
-		var i int
-		i, err = strconv.Atoi(lastKey)
+			x[j].Value = wrapper(value)
+			return x, nil
+		}
+
+		var v any = D{}
+		if j >= 0 {
+			v = x[j].Value
+		}
+		v, err := setKeys(v, key, segs, i+1, value)
 		if err != nil {
-			return
+			return nil, err
 		}
-		if i < 0 || i >= len(x) {
-			err = fmt.Errorf("not found key %q", key)
-			return
+		if j < 0 {
+			return append(x, E{k, v}), nil
+		}
+		x[j].Value = v
+		return x, nil
+	case A:
+		if !segs[i].ok {
+			_, err := strconv.Atoi(k)
+			return nil, err
+		}
+		j, ok := segs[i].element(len(x))
+		if !ok {
+			return nil, fmt.Errorf("not found key %q", key[:segs[i].end])
+		}
+		if last {
+			x[j] = wrapper(value)
+			return x, nil
 		}
 
-		defer func() {
-			if r := recover(); r != nil {
-				err = fmt.Errorf("%v", r)
-			}
-		}()
-		return x[i].(E), nil
+		v, err := setKeys(x[j], key, segs, i+1, value)
+		if err != nil {
+			return nil, err
+		}
+		x[j] = v
+		return x, nil
+	}
+}
+
+// deleteKeys removes the value for segs within a. It returns a, or the D or A
+// replacing it if it had to shrink.
+func deleteKeys(a any, segs []segment) any {
+	k, last := segs[0].key, len(segs) == 1
+	switch x := a.(type) {
+	default:
+		return a
+	case M:
+		if last {
+			delete(x, k)
+		} else if v, ok := x[k]; ok {
+			x[k] = deleteKeys(v, segs[1:])
+		}
+		return x
+	case D:
+		j := x.index(k)
+		if j < 0 {
+			return x
+		}
+		if last {
+			return append(x[:j], x[j+1:]...)
+		}
+		x[j].Value = deleteKeys(x[j].Value, segs[1:])
+		return x
+	case A:
+		if segs[0].slice && last {
+			lo, hi := segs[0].bounds(len(x))
+			return append(x[:lo], x[hi:]...)
+		}
+		j, ok := segs[0].element(len(x))
+		if !ok {
+			return x
+		}
+		if last {
+			return append(x[:j], x[j+1:]...)
+		}
+		x[j] = deleteKeys(x[j], segs[1:])
+		return x
 	}
 }
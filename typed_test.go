@@ -113,6 +113,51 @@ func TestM(t *testing.T) {
 	equalSlice(t, []string{"Gomez", "Morticia"}, m.Array("Parents").Strings())
 }
 
+func TestM_Set(t *testing.T) {
+	t.Parallel()
+
+	var j = []byte(`{
+		"Name": "Wednesday",
+		"Parents": ["Gomez", "Morticia"]
+	}`)
+	var m M
+	err := json.Unmarshal(j, &m)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m.Set("Name", "Pugsley")
+	m.Set("Profile.Age", float64(6))
+	m.Set("Parents.1", map[string]any{"Name": "Morticia"})
+
+	equal(t, "Pugsley", m.StringValue("Name"))
+	equal(t, 6, m.AsInt("Profile.Age"))
+	equal(t, "Morticia", m.StringValue("Parents.1.Name"))
+
+	equal(t, false, m.SetOK("Parents.2", "Fester"))
+	equal(t, false, m.SetOK("Name.First", "Wednesday"))
+	equal(t, true, panics(func() { M(nil).Set("Name", "Wednesday") }))
+}
+
+func TestM_Delete(t *testing.T) {
+	t.Parallel()
+
+	m := M{
+		"Name":    "Wednesday",
+		"Profile": M{"Age": float64(6)},
+		"Parents": A{"Gomez", "Morticia"},
+	}
+
+	m.Delete("Profile.Age")
+	m.Delete("Parents.0")
+	m.Delete("NotExistsKey.Name")
+
+	equal(t, false, m.Exists("Profile.Age"))
+	equal(t, true, m.Exists("Profile"))
+	equalSlice(t, []string{"Morticia"}, m.Array("Parents").Strings())
+	equalSlice(t, []string{"Name", "Parents", "Profile"}, m.Keys())
+}
+
 func TestM_Keys(t *testing.T) {
 	t.Parallel()
 
@@ -381,6 +426,45 @@ func TestA_Primitives(t *testing.T) {
 	equal(t, 2, a[2].(float64))
 }
 
+func TestA_Accessors(t *testing.T) {
+	t.Parallel()
+
+	var j = []byte(`[6, {"Name": "Wednesday", "Born": "2004-10-31T00:00:00Z", "Nap": "1h"}, "Gomez", [true], null]`)
+	var a A
+	err := json.Unmarshal(j, &a)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	equal(t, true, a.Exists("3"))
+	equal(t, false, a.Exists("5"))
+	equal(t, true, a.IsNumber("0"))
+	equal(t, 6, a.AsInt("0"))
+	equal(t, int64(6), a.AsInt64("0"))
+	equal(t, 6.0, a.Float("0"))
+	equal(t, "Wednesday", a.StringValue("1.Name"))
+	equal(t, "Gomez", a.StringValue("-3"))
+	equal(t, true, a.Bool("3.0"))
+	equal(t, 2004, a.AsTime("1.Born").Year())
+	equal(t, time.Hour, a.AsDuration("1.Nap"))
+	equal(t, "Wednesday", a.Document("1").StringValue("Name"))
+	equal(t, "Wednesday", a.Map("1")["Name"].(string))
+	equal(t, 1, len(a.Array("3")))
+	equal(t, 2, len(a.Array("1:3")))
+	equal(t, `{"Born":"2004-10-31T00:00:00Z","Name":"Wednesday","Nap":"1h"}`, string(a.RawMessage("1")))
+	equal(t, "null", string(a.RawMessage("Name")))
+	equal(t, "Gomez", a.Any("2").(string))
+
+	_, ok := a.StringValueOK("0")
+	equal(t, false, ok)
+	_, ok = a.DocumentOK("2")
+	equal(t, false, ok)
+	equal(t, true, panics(func() { a.StringValue("Name") }))
+
+	var g Getter = a
+	equal(t, 6, g.AsInt("0"))
+}
+
 func equal[T comparable](tb testing.TB, expected, actual T) {
 	tb.Helper()
 
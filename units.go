@@ -0,0 +1,445 @@
+package typed
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var byteUnits = map[string]int64{
+	"":    1,
+	"b":   1,
+	"k":   1e3,
+	"kb":  1e3,
+	"m":   1e6,
+	"mb":  1e6,
+	"g":   1e9,
+	"gb":  1e9,
+	"t":   1e12,
+	"tb":  1e12,
+	"p":   1e15,
+	"pb":  1e15,
+	"e":   1e18,
+	"eb":  1e18,
+	"ki":  1 << 10,
+	"kib": 1 << 10,
+	"mi":  1 << 20,
+	"mib": 1 << 20,
+	"gi":  1 << 30,
+	"gib": 1 << 30,
+	"ti":  1 << 40,
+	"tib": 1 << 40,
+	"pi":  1 << 50,
+	"pib": 1 << 50,
+	"ei":  1 << 60,
+	"eib": 1 << 60,
+}
+
+// ParseByteSize parses a size in bytes, such as "512MiB", "10GB" or "1.5 KiB". The
+// unit is case insensitive: SI units, such as "MB" or "M", are powers of 1000, and
+// IEC units, such as "MiB" or "Mi", powers of 1024. A size without a unit is in bytes.
+// The size must be a whole, non-negative number of bytes within the range of int64.
+func ParseByteSize(s string) (int64, error) {
+	num := strings.TrimRight(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ")
+	unit, ok := byteUnits[strings.ToLower(s[len(num):])]
+	num = strings.TrimSpace(num)
+	if !ok || num == "" {
+		return 0, fmt.Errorf("invalid byte size %q", s)
+	}
+
+	if strings.HasPrefix(num, "-") {
+		return 0, fmt.Errorf("negative byte size %q", s)
+	}
+
+	if n, err := strconv.ParseInt(num, 10, 64); err == nil {
+		if n > math.MaxInt64/unit {
+			return 0, fmt.Errorf("byte size %q overflows int64", s)
+		}
+		return n * unit, nil
+	}
+
+	f, err := strconv.ParseFloat(num, 64)
+	if err != nil || math.IsInf(f, 0) || math.IsNaN(f) {
+		return 0, fmt.Errorf("invalid byte size %q", s)
+	}
+	return wholeBytes(f*float64(unit), s)
+}
+
+func wholeBytes(f float64, s string) (int64, error) {
+	if f != math.Trunc(f) {
+		return 0, fmt.Errorf("byte size %q is not a whole number of bytes", s)
+	}
+	if f < 0 {
+		return 0, fmt.Errorf("negative byte size %q", s)
+	}
+	if f >= math.MaxInt64 {
+		return 0, fmt.Errorf("byte size %q overflows int64", s)
+	}
+	return int64(f), nil
+}
+
+// ParsePercent parses a percentage, such as "75%", as a fraction, 0.75. A number
+// without a percent sign is a fraction already.
+func ParsePercent(s string) (float64, error) {
+	num, isPercent := strings.CutSuffix(strings.TrimSpace(s), "%")
+	f, err := strconv.ParseFloat(strings.TrimSpace(num), 64)
+	if err != nil || math.IsInf(f, 0) || math.IsNaN(f) {
+		return 0, fmt.Errorf("invalid percentage %q", s)
+	}
+	if isPercent {
+		f /= 100
+	}
+	return f, nil
+}
+
+// A Rate is a number of events per period, such as 100 per second for "100/s".
+type Rate struct {
+	Count float64
+	Per   time.Duration
+}
+
+// PerSecond returns the number of events per second.
+func (r Rate) PerSecond() float64 {
+	return r.Count / r.Per.Seconds()
+}
+
+// Interval returns the mean time between two events.
+func (r Rate) Interval() time.Duration {
+	return time.Duration(float64(r.Per) / r.Count)
+}
+
+func (r Rate) String() string {
+	return strconv.FormatFloat(r.Count, 'g', -1, 64) + "/" + r.Per.String()
+}
+
+var rateUnits = map[string]string{
+	"sec":    "s",
+	"second": "s",
+	"min":    "m",
+	"minute": "m",
+	"hr":     "h",
+	"hour":   "h",
+	"day":    "d",
+	"week":   "w",
+}
+
+// ParseRate parses a rate, such as "100/s", "5/min" or "1000/2h": a count, a slash and
+// a period as ParseDuration accepts it, whose count defaults to 1. The count must be
+// positive. The periods "sec",
+// "second", "min", "minute", "hr", "hour", "day" and "week" are also accepted.
+func ParseRate(s string) (Rate, error) {
+	count, per, ok := strings.Cut(s, "/")
+	if !ok {
+		return Rate{}, fmt.Errorf("invalid rate %q", s)
+	}
+	n, err := strconv.ParseFloat(strings.TrimSpace(count), 64)
+	if err != nil || n <= 0 || math.IsInf(n, 0) || math.IsNaN(n) {
+		return Rate{}, fmt.Errorf("invalid rate %q", s)
+	}
+
+	per = strings.TrimSpace(per)
+	if u, ok := rateUnits[strings.ToLower(per)]; ok {
+		per = u
+	}
+	if per != "" && (per[0] < '0' || per[0] > '9') && per[0] != '.' {
+		per = "1" + per
+	}
+	d, err := ParseDuration(per)
+	if err != nil || d <= 0 {
+		return Rate{}, fmt.Errorf("invalid period in rate %q", s)
+	}
+	return Rate{Count: n, Per: d}, nil
+}
+
+// byteSize returns the size in bytes v represents: a number of bytes, or a string as
+// ParseByteSize accepts it.
+func byteSize(v any) (int64, error) {
+	switch x := v.(type) {
+	case float64:
+		return wholeBytes(x, strconv.FormatFloat(x, 'g', -1, 64))
+	case json.Number:
+		return ParseByteSize(string(x))
+	}
+	s, err := convert[string](v)
+	if err != nil {
+		return 0, err
+	}
+	return ParseByteSize(s)
+}
+
+// percent returns the fraction v represents: a number, or a string as ParsePercent
+// accepts it.
+func percent(v any) (float64, error) {
+	if f, ok := toFloat(v); ok {
+		return f, nil
+	}
+	s, err := convert[string](v)
+	if err != nil {
+		return 0, err
+	}
+	return ParsePercent(s)
+}
+
+// rate returns the rate v represents: a positive number of events per second, or a
+// string as ParseRate accepts it.
+func rate(v any) (Rate, error) {
+	if f, ok := toFloat(v); ok {
+		if f <= 0 || math.IsInf(f, 0) {
+			return Rate{}, fmt.Errorf("invalid rate %v", f)
+		}
+		return Rate{Count: f, Per: time.Second}, nil
+	}
+	s, err := convert[string](v)
+	if err != nil {
+		return Rate{}, err
+	}
+	return ParseRate(s)
+}
+
+// convertValue converts the value for given key within a with conv.
+func convertValue[T any](a any, key string, conv func(any) (T, error)) (T, error) {
+	v, err := lookupErr[any](a, key)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return conv(v)
+}
+
+// convertArray converts the elements of a with conv.
+func convertArray[T any](a A, conv func(any) (T, error)) ([]T, error) {
+	s := make([]T, len(a))
+	for i, v := range a {
+		var err error
+		if s[i], err = conv(v); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// or returns v, or def if err is not nil.
+func or[T any](v T, err error, def T) T {
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+// AsByteSize returns the int64 the value represents for given key. It panics if the
+// value is neither a number nor a string holding a size in bytes ParseByteSize accepts.
+// A number is a number of bytes.
+func (m M) AsByteSize(key string) int64 {
+	return must(convertValue(m, key, byteSize))
+}
+
+// AsByteSizeOK is the same as AsByteSize, except it returns a boolean instead of
+// panicking.
+func (m M) AsByteSizeOK(key string) (int64, bool) {
+	v, err := convertValue(m, key, byteSize)
+	return v, err == nil
+}
+
+// AsByteSizeOr is the same as AsByteSize, except it returns def instead of panicking.
+func (m M) AsByteSizeOr(key string, def int64) int64 {
+	v, err := convertValue(m, key, byteSize)
+	return or(v, err, def)
+}
+
+// AsPercent returns the float64 the value represents for given key. It panics if the
+// value is neither a number nor a string holding a percentage ParsePercent accepts. A
+// number is a fraction.
+func (m M) AsPercent(key string) float64 {
+	return must(convertValue(m, key, percent))
+}
+
+// AsPercentOK is the same as AsPercent, except it returns a boolean instead of
+// panicking.
+func (m M) AsPercentOK(key string) (float64, bool) {
+	v, err := convertValue(m, key, percent)
+	return v, err == nil
+}
+
+// AsPercentOr is the same as AsPercent, except it returns def instead of panicking.
+func (m M) AsPercentOr(key string, def float64) float64 {
+	v, err := convertValue(m, key, percent)
+	return or(v, err, def)
+}
+
+// AsRate returns the Rate the value represents for given key. It panics if the value is
+// neither a number nor a string holding a rate ParseRate accepts. A number is a number
+// of events per second.
+func (m M) AsRate(key string) Rate {
+	return must(convertValue(m, key, rate))
+}
+
+// AsRateOK is the same as AsRate, except it returns a boolean instead of panicking.
+func (m M) AsRateOK(key string) (Rate, bool) {
+	v, err := convertValue(m, key, rate)
+	return v, err == nil
+}
+
+// AsRateOr is the same as AsRate, except it returns def instead of panicking.
+func (m M) AsRateOr(key string, def Rate) Rate {
+	v, err := convertValue(m, key, rate)
+	return or(v, err, def)
+}
+
+// AsByteSize returns the int64 the value represents for given key. It panics if the
+// value is neither a number nor a string holding a size in bytes ParseByteSize accepts.
+// A number is a number of bytes.
+func (d D) AsByteSize(key string) int64 {
+	return must(convertValue(d, key, byteSize))
+}
+
+// AsByteSizeOK is the same as AsByteSize, except it returns a boolean instead of
+// panicking.
+func (d D) AsByteSizeOK(key string) (int64, bool) {
+	v, err := convertValue(d, key, byteSize)
+	return v, err == nil
+}
+
+// AsByteSizeOr is the same as AsByteSize, except it returns def instead of panicking.
+func (d D) AsByteSizeOr(key string, def int64) int64 {
+	v, err := convertValue(d, key, byteSize)
+	return or(v, err, def)
+}
+
+// AsPercent returns the float64 the value represents for given key. It panics if the
+// value is neither a number nor a string holding a percentage ParsePercent accepts. A
+// number is a fraction.
+func (d D) AsPercent(key string) float64 {
+	return must(convertValue(d, key, percent))
+}
+
+// AsPercentOK is the same as AsPercent, except it returns a boolean instead of
+// panicking.
+func (d D) AsPercentOK(key string) (float64, bool) {
+	v, err := convertValue(d, key, percent)
+	return v, err == nil
+}
+
+// AsPercentOr is the same as AsPercent, except it returns def instead of panicking.
+func (d D) AsPercentOr(key string, def float64) float64 {
+	v, err := convertValue(d, key, percent)
+	return or(v, err, def)
+}
+
+// AsRate returns the Rate the value represents for given key. It panics if the value is
+// neither a number nor a string holding a rate ParseRate accepts. A number is a number
+// of events per second.
+func (d D) AsRate(key string) Rate {
+	return must(convertValue(d, key, rate))
+}
+
+// AsRateOK is the same as AsRate, except it returns a boolean instead of panicking.
+func (d D) AsRateOK(key string) (Rate, bool) {
+	v, err := convertValue(d, key, rate)
+	return v, err == nil
+}
+
+// AsRateOr is the same as AsRate, except it returns def instead of panicking.
+func (d D) AsRateOr(key string, def Rate) Rate {
+	v, err := convertValue(d, key, rate)
+	return or(v, err, def)
+}
+
+// AsByteSize returns the int64 the value represents for given key. It panics if the
+// value is neither a number nor a string holding a size in bytes ParseByteSize accepts.
+// A number is a number of bytes.
+func (a A) AsByteSize(key string) int64 {
+	return must(convertValue(a, key, byteSize))
+}
+
+// AsByteSizeOK is the same as AsByteSize, except it returns a boolean instead of
+// panicking.
+func (a A) AsByteSizeOK(key string) (int64, bool) {
+	v, err := convertValue(a, key, byteSize)
+	return v, err == nil
+}
+
+// AsByteSizeOr is the same as AsByteSize, except it returns def instead of panicking.
+func (a A) AsByteSizeOr(key string, def int64) int64 {
+	v, err := convertValue(a, key, byteSize)
+	return or(v, err, def)
+}
+
+// AsPercent returns the float64 the value represents for given key. It panics if the
+// value is neither a number nor a string holding a percentage ParsePercent accepts. A
+// number is a fraction.
+func (a A) AsPercent(key string) float64 {
+	return must(convertValue(a, key, percent))
+}
+
+// AsPercentOK is the same as AsPercent, except it returns a boolean instead of
+// panicking.
+func (a A) AsPercentOK(key string) (float64, bool) {
+	v, err := convertValue(a, key, percent)
+	return v, err == nil
+}
+
+// AsPercentOr is the same as AsPercent, except it returns def instead of panicking.
+func (a A) AsPercentOr(key string, def float64) float64 {
+	v, err := convertValue(a, key, percent)
+	return or(v, err, def)
+}
+
+// AsRate returns the Rate the value represents for given key. It panics if the value is
+// neither a number nor a string holding a rate ParseRate accepts. A number is a number
+// of events per second.
+func (a A) AsRate(key string) Rate {
+	return must(convertValue(a, key, rate))
+}
+
+// AsRateOK is the same as AsRate, except it returns a boolean instead of panicking.
+func (a A) AsRateOK(key string) (Rate, bool) {
+	v, err := convertValue(a, key, rate)
+	return v, err == nil
+}
+
+// AsRateOr is the same as AsRate, except it returns def instead of panicking.
+func (a A) AsRateOr(key string, def Rate) Rate {
+	v, err := convertValue(a, key, rate)
+	return or(v, err, def)
+}
+
+// AsByteSizes returns the slice of int64 the array represents, as AsByteSize converts
+// its elements. It panics if one of elements can not be converted.
+func (a A) AsByteSizes() []int64 {
+	return must(convertArray(a, byteSize))
+}
+
+// AsByteSizesOK is the same as AsByteSizes, except it returns a boolean instead of
+// panicking.
+func (a A) AsByteSizesOK() ([]int64, bool) {
+	s, err := convertArray(a, byteSize)
+	return s, err == nil
+}
+
+// AsPercents returns the slice of float64 the array represents, as AsPercent converts
+// its elements. It panics if one of elements can not be converted.
+func (a A) AsPercents() []float64 {
+	return must(convertArray(a, percent))
+}
+
+// AsPercentsOK is the same as AsPercents, except it returns a boolean instead of
+// panicking.
+func (a A) AsPercentsOK() ([]float64, bool) {
+	s, err := convertArray(a, percent)
+	return s, err == nil
+}
+
+// AsRates returns the slice of Rate the array represents, as AsRate converts its
+// elements. It panics if one of elements can not be converted.
+func (a A) AsRates() []Rate {
+	return must(convertArray(a, rate))
+}
+
+// AsRatesOK is the same as AsRates, except it returns a boolean instead of panicking.
+func (a A) AsRatesOK() ([]Rate, bool) {
+	s, err := convertArray(a, rate)
+	return s, err == nil
+}
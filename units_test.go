@@ -0,0 +1,124 @@
+package typed
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseByteSize(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]int64{
+		"0":                    0,
+		"512":                  512,
+		"512B":                 512,
+		"1k":                   1000,
+		"10GB":                 10e9,
+		"512MiB":               512 << 20,
+		"512Mi":                512 << 20,
+		"1.5 KiB":              1536,
+		"8EiB":                 -1,
+		"7EiB":                 7 << 60,
+		"1.1B":                 -1,
+		"10 XB":                -1,
+		"MB":                   -1,
+		"":                     -1,
+		"9223372036854775807":  1<<63 - 1,
+		"9223372036854775807k": -1,
+	}
+	for s, want := range tests {
+		got, err := ParseByteSize(s)
+		if want < 0 {
+			if err == nil {
+				t.Errorf("%q: no error", s)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: %v", s, err)
+			continue
+		}
+		equal(t, want, got)
+	}
+
+	_, err := ParseByteSize("8EiB")
+	equal(t, `byte size "8EiB" overflows int64`, err.Error())
+}
+
+func TestParsePercent(t *testing.T) {
+	t.Parallel()
+
+	for s, want := range map[string]float64{"75%": 0.75, "0.5": 0.5, " 12.5 % ": 0.125, "150%": 1.5} {
+		got, err := ParsePercent(s)
+		equal(t, nil, err)
+		equal(t, want, got)
+	}
+	for _, s := range []string{"", "%", "abc%", "NaN%"} {
+		_, err := ParsePercent(s)
+		equal(t, true, err != nil)
+	}
+}
+
+func TestParseRate(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]Rate{
+		"100/s":      {100, time.Second},
+		"5/min":      {5, time.Minute},
+		"1000/2h":    {1000, 2 * time.Hour},
+		"2.5 / hour": {2.5, time.Hour},
+		"10/d":       {10, 24 * time.Hour},
+	}
+	for s, want := range tests {
+		got, err := ParseRate(s)
+		if err != nil {
+			t.Errorf("%q: %v", s, err)
+			continue
+		}
+		equal(t, want, got)
+	}
+	for _, s := range []string{"100", "x/s", "-1/s", "0/s", "1/0s", "1/fortnight"} {
+		_, err := ParseRate(s)
+		equal(t, true, err != nil)
+	}
+
+	r := Rate{Count: 120, Per: time.Minute}
+	equal(t, 2.0, r.PerSecond())
+	equal(t, 500*time.Millisecond, r.Interval())
+	equal(t, "120/1m0s", r.String())
+}
+
+func TestUnitAccessors(t *testing.T) {
+	t.Parallel()
+
+	m := M{
+		"cache":  M{"size": "512MiB", "ratio": "75%", "limit": "100/s"},
+		"sizes":  A{"1KiB", 2048.0, "3k"},
+		"ratios": A{0.5, "25%"},
+		"limits": A{"5/min", 10.0},
+		"bad":    "lots",
+	}
+	equal(t, int64(512<<20), m.AsByteSize("cache.size"))
+	equal(t, 0.75, m.AsPercent("cache.ratio"))
+	equal(t, 100.0, m.AsRate("cache.limit").PerSecond())
+	equalSlice(t, []int64{1024, 2048, 3000}, m.Array("sizes").AsByteSizes())
+	equalSlice(t, []float64{0.5, 0.25}, m.Array("ratios").AsPercents())
+	equalSlice(t, []Rate{{5, time.Minute}, {10, time.Second}}, m.Array("limits").AsRates())
+
+	equal(t, int64(1<<20), m.AsByteSizeOr("missing", 1<<20))
+	equal(t, int64(1<<20), m.AsByteSizeOr("bad", 1<<20))
+	equal(t, 0.1, D{{"ratio", "10%"}}.AsPercentOr("ratio", 0.5))
+	equal(t, Rate{1, time.Second}, A{"x"}.AsRateOr("0", Rate{1, time.Second}))
+	_, ok := m.AsPercentOK("bad")
+	equal(t, false, ok)
+	_, ok = A{"1KiB", true}.AsByteSizesOK()
+	equal(t, false, ok)
+	equal(t, true, panics(func() { m.AsRate("cache.size") }))
+	_, ok = A{0.0}.AsRatesOK()
+	equal(t, false, ok)
+	equal(t, true, panics(func() { A{0.5}.AsByteSizes() }))
+	equal(t, true, panics(func() { A{-1.0}.AsByteSizes() }))
+
+	_, err := ParseByteSize("-5MB")
+	equal(t, `negative byte size "-5MB"`, err.Error())
+}
@@ -0,0 +1,471 @@
+package typed
+
+import (
+	"encoding"
+	"encoding/base64"
+	"fmt"
+	"net/mail"
+	"net/netip"
+	"net/url"
+	"regexp"
+)
+
+// AsURL returns the *url.URL the value represents for given key. It panics if the value
+// is not a string holding a URL url.Parse accepts.
+func (m M) AsURL(key string) *url.URL {
+	return must(parseValue(m, key, url.Parse))
+}
+
+// AsURLOK is the same as AsURL, except it returns a boolean instead of panicking.
+func (m M) AsURLOK(key string) (*url.URL, bool) {
+	v, err := parseValue(m, key, url.Parse)
+	return v, err == nil
+}
+
+// AsAddr returns the netip.Addr the value represents for given key. It panics if the
+// value is not a string holding an IP address netip.ParseAddr accepts.
+func (m M) AsAddr(key string) netip.Addr {
+	return must(parseValue(m, key, netip.ParseAddr))
+}
+
+// AsAddrOK is the same as AsAddr, except it returns a boolean instead of panicking.
+func (m M) AsAddrOK(key string) (netip.Addr, bool) {
+	v, err := parseValue(m, key, netip.ParseAddr)
+	return v, err == nil
+}
+
+// AsPrefix returns the netip.Prefix the value represents for given key. It panics if
+// the value is not a string holding an IP prefix in CIDR notation netip.ParsePrefix
+// accepts.
+func (m M) AsPrefix(key string) netip.Prefix {
+	return must(parseValue(m, key, netip.ParsePrefix))
+}
+
+// AsPrefixOK is the same as AsPrefix, except it returns a boolean instead of panicking.
+func (m M) AsPrefixOK(key string) (netip.Prefix, bool) {
+	v, err := parseValue(m, key, netip.ParsePrefix)
+	return v, err == nil
+}
+
+// AsAddrPort returns the netip.AddrPort the value represents for given key. It panics
+// if the value is not a string holding an IP address and port netip.ParseAddrPort
+// accepts.
+func (m M) AsAddrPort(key string) netip.AddrPort {
+	return must(parseValue(m, key, netip.ParseAddrPort))
+}
+
+// AsAddrPortOK is the same as AsAddrPort, except it returns a boolean instead of
+// panicking.
+func (m M) AsAddrPortOK(key string) (netip.AddrPort, bool) {
+	v, err := parseValue(m, key, netip.ParseAddrPort)
+	return v, err == nil
+}
+
+// AsBytes returns the []byte the value represents for given key. It panics if the value
+// is not a string holding base64 in any of the standard and URL encodings, padded or
+// not.
+func (m M) AsBytes(key string) []byte {
+	return must(parseValue(m, key, decodeBase64))
+}
+
+// AsBytesOK is the same as AsBytes, except it returns a boolean instead of panicking.
+func (m M) AsBytesOK(key string) ([]byte, bool) {
+	v, err := parseValue(m, key, decodeBase64)
+	return v, err == nil
+}
+
+// AsRegexp returns the *regexp.Regexp the value represents for given key. It panics if
+// the value is not a string holding a regular expression regexp.Compile accepts.
+func (m M) AsRegexp(key string) *regexp.Regexp {
+	return must(parseValue(m, key, regexp.Compile))
+}
+
+// AsRegexpOK is the same as AsRegexp, except it returns a boolean instead of panicking.
+func (m M) AsRegexpOK(key string) (*regexp.Regexp, bool) {
+	v, err := parseValue(m, key, regexp.Compile)
+	return v, err == nil
+}
+
+// AsMailAddress returns the *mail.Address the value represents for given key. It panics
+// if the value is not a string holding an RFC 5322 address mail.ParseAddress accepts.
+func (m M) AsMailAddress(key string) *mail.Address {
+	return must(parseValue(m, key, mail.ParseAddress))
+}
+
+// AsMailAddressOK is the same as AsMailAddress, except it returns a boolean instead of
+// panicking.
+func (m M) AsMailAddressOK(key string) (*mail.Address, bool) {
+	v, err := parseValue(m, key, mail.ParseAddress)
+	return v, err == nil
+}
+
+// AsText decodes the value for given key into v with its UnmarshalText method. It
+// panics if the value is not a string, or if it is rejected by v.
+func (m M) AsText(key string, v encoding.TextUnmarshaler) {
+	if err := asTextErr(m, key, v); err != nil {
+		panic(err)
+	}
+}
+
+// AsTextOK is the same as AsText, except it returns a boolean instead of panicking.
+func (m M) AsTextOK(key string, v encoding.TextUnmarshaler) bool {
+	return asTextErr(m, key, v) == nil
+}
+
+// AsURL returns the *url.URL the value represents for given key. It panics if the value
+// is not a string holding a URL url.Parse accepts.
+func (d D) AsURL(key string) *url.URL {
+	return must(parseValue(d, key, url.Parse))
+}
+
+// AsURLOK is the same as AsURL, except it returns a boolean instead of panicking.
+func (d D) AsURLOK(key string) (*url.URL, bool) {
+	v, err := parseValue(d, key, url.Parse)
+	return v, err == nil
+}
+
+// AsAddr returns the netip.Addr the value represents for given key. It panics if the
+// value is not a string holding an IP address netip.ParseAddr accepts.
+func (d D) AsAddr(key string) netip.Addr {
+	return must(parseValue(d, key, netip.ParseAddr))
+}
+
+// AsAddrOK is the same as AsAddr, except it returns a boolean instead of panicking.
+func (d D) AsAddrOK(key string) (netip.Addr, bool) {
+	v, err := parseValue(d, key, netip.ParseAddr)
+	return v, err == nil
+}
+
+// AsPrefix returns the netip.Prefix the value represents for given key. It panics if
+// the value is not a string holding an IP prefix in CIDR notation netip.ParsePrefix
+// accepts.
+func (d D) AsPrefix(key string) netip.Prefix {
+	return must(parseValue(d, key, netip.ParsePrefix))
+}
+
+// AsPrefixOK is the same as AsPrefix, except it returns a boolean instead of panicking.
+func (d D) AsPrefixOK(key string) (netip.Prefix, bool) {
+	v, err := parseValue(d, key, netip.ParsePrefix)
+	return v, err == nil
+}
+
+// AsAddrPort returns the netip.AddrPort the value represents for given key. It panics
+// if the value is not a string holding an IP address and port netip.ParseAddrPort
+// accepts.
+func (d D) AsAddrPort(key string) netip.AddrPort {
+	return must(parseValue(d, key, netip.ParseAddrPort))
+}
+
+// AsAddrPortOK is the same as AsAddrPort, except it returns a boolean instead of
+// panicking.
+func (d D) AsAddrPortOK(key string) (netip.AddrPort, bool) {
+	v, err := parseValue(d, key, netip.ParseAddrPort)
+	return v, err == nil
+}
+
+// AsBytes returns the []byte the value represents for given key. It panics if the value
+// is not a string holding base64 in any of the standard and URL encodings, padded or
+// not.
+func (d D) AsBytes(key string) []byte {
+	return must(parseValue(d, key, decodeBase64))
+}
+
+// AsBytesOK is the same as AsBytes, except it returns a boolean instead of panicking.
+func (d D) AsBytesOK(key string) ([]byte, bool) {
+	v, err := parseValue(d, key, decodeBase64)
+	return v, err == nil
+}
+
+// AsRegexp returns the *regexp.Regexp the value represents for given key. It panics if
+// the value is not a string holding a regular expression regexp.Compile accepts.
+func (d D) AsRegexp(key string) *regexp.Regexp {
+	return must(parseValue(d, key, regexp.Compile))
+}
+
+// AsRegexpOK is the same as AsRegexp, except it returns a boolean instead of panicking.
+func (d D) AsRegexpOK(key string) (*regexp.Regexp, bool) {
+	v, err := parseValue(d, key, regexp.Compile)
+	return v, err == nil
+}
+
+// AsMailAddress returns the *mail.Address the value represents for given key. It panics
+// if the value is not a string holding an RFC 5322 address mail.ParseAddress accepts.
+func (d D) AsMailAddress(key string) *mail.Address {
+	return must(parseValue(d, key, mail.ParseAddress))
+}
+
+// AsMailAddressOK is the same as AsMailAddress, except it returns a boolean instead of
+// panicking.
+func (d D) AsMailAddressOK(key string) (*mail.Address, bool) {
+	v, err := parseValue(d, key, mail.ParseAddress)
+	return v, err == nil
+}
+
+// AsText decodes the value for given key into v with its UnmarshalText method. It
+// panics if the value is not a string, or if it is rejected by v.
+func (d D) AsText(key string, v encoding.TextUnmarshaler) {
+	if err := asTextErr(d, key, v); err != nil {
+		panic(err)
+	}
+}
+
+// AsTextOK is the same as AsText, except it returns a boolean instead of panicking.
+func (d D) AsTextOK(key string, v encoding.TextUnmarshaler) bool {
+	return asTextErr(d, key, v) == nil
+}
+
+// AsURL returns the *url.URL the value represents for given key. It panics if the value
+// is not a string holding a URL url.Parse accepts.
+func (a A) AsURL(key string) *url.URL {
+	return must(parseValue(a, key, url.Parse))
+}
+
+// AsURLOK is the same as AsURL, except it returns a boolean instead of panicking.
+func (a A) AsURLOK(key string) (*url.URL, bool) {
+	v, err := parseValue(a, key, url.Parse)
+	return v, err == nil
+}
+
+// AsAddr returns the netip.Addr the value represents for given key. It panics if the
+// value is not a string holding an IP address netip.ParseAddr accepts.
+func (a A) AsAddr(key string) netip.Addr {
+	return must(parseValue(a, key, netip.ParseAddr))
+}
+
+// AsAddrOK is the same as AsAddr, except it returns a boolean instead of panicking.
+func (a A) AsAddrOK(key string) (netip.Addr, bool) {
+	v, err := parseValue(a, key, netip.ParseAddr)
+	return v, err == nil
+}
+
+// AsPrefix returns the netip.Prefix the value represents for given key. It panics if
+// the value is not a string holding an IP prefix in CIDR notation netip.ParsePrefix
+// accepts.
+func (a A) AsPrefix(key string) netip.Prefix {
+	return must(parseValue(a, key, netip.ParsePrefix))
+}
+
+// AsPrefixOK is the same as AsPrefix, except it returns a boolean instead of panicking.
+func (a A) AsPrefixOK(key string) (netip.Prefix, bool) {
+	v, err := parseValue(a, key, netip.ParsePrefix)
+	return v, err == nil
+}
+
+// AsAddrPort returns the netip.AddrPort the value represents for given key. It panics
+// if the value is not a string holding an IP address and port netip.ParseAddrPort
+// accepts.
+func (a A) AsAddrPort(key string) netip.AddrPort {
+	return must(parseValue(a, key, netip.ParseAddrPort))
+}
+
+// AsAddrPortOK is the same as AsAddrPort, except it returns a boolean instead of
+// panicking.
+func (a A) AsAddrPortOK(key string) (netip.AddrPort, bool) {
+	v, err := parseValue(a, key, netip.ParseAddrPort)
+	return v, err == nil
+}
+
+// AsBytes returns the []byte the value represents for given key. It panics if the value
+// is not a string holding base64 in any of the standard and URL encodings, padded or
+// not.
+func (a A) AsBytes(key string) []byte {
+	return must(parseValue(a, key, decodeBase64))
+}
+
+// AsBytesOK is the same as AsBytes, except it returns a boolean instead of panicking.
+func (a A) AsBytesOK(key string) ([]byte, bool) {
+	v, err := parseValue(a, key, decodeBase64)
+	return v, err == nil
+}
+
+// AsRegexp returns the *regexp.Regexp the value represents for given key. It panics if
+// the value is not a string holding a regular expression regexp.Compile accepts.
+func (a A) AsRegexp(key string) *regexp.Regexp {
+	return must(parseValue(a, key, regexp.Compile))
+}
+
+// AsRegexpOK is the same as AsRegexp, except it returns a boolean instead of panicking.
+func (a A) AsRegexpOK(key string) (*regexp.Regexp, bool) {
+	v, err := parseValue(a, key, regexp.Compile)
+	return v, err == nil
+}
+
+// AsMailAddress returns the *mail.Address the value represents for given key. It panics
+// if the value is not a string holding an RFC 5322 address mail.ParseAddress accepts.
+func (a A) AsMailAddress(key string) *mail.Address {
+	return must(parseValue(a, key, mail.ParseAddress))
+}
+
+// AsMailAddressOK is the same as AsMailAddress, except it returns a boolean instead of
+// panicking.
+func (a A) AsMailAddressOK(key string) (*mail.Address, bool) {
+	v, err := parseValue(a, key, mail.ParseAddress)
+	return v, err == nil
+}
+
+// AsText decodes the value for given key into v with its UnmarshalText method. It
+// panics if the value is not a string, or if it is rejected by v.
+func (a A) AsText(key string, v encoding.TextUnmarshaler) {
+	if err := asTextErr(a, key, v); err != nil {
+		panic(err)
+	}
+}
+
+// AsTextOK is the same as AsText, except it returns a boolean instead of panicking.
+func (a A) AsTextOK(key string, v encoding.TextUnmarshaler) bool {
+	return asTextErr(a, key, v) == nil
+}
+
+// AsURLs returns the slice of *url.URL the array represents. It panics if one of
+// elements is not a string holding a URL url.Parse accepts.
+func (a A) AsURLs() []*url.URL {
+	return must(parseArray(a, url.Parse))
+}
+
+// AsURLsOK is the same as AsURLs, except it returns a boolean instead of panicking.
+func (a A) AsURLsOK() ([]*url.URL, bool) {
+	s, err := parseArray(a, url.Parse)
+	return s, err == nil
+}
+
+// AsAddrs returns the slice of netip.Addr the array represents. It panics if one of
+// elements is not a string holding an IP address netip.ParseAddr accepts.
+func (a A) AsAddrs() []netip.Addr {
+	return must(parseArray(a, netip.ParseAddr))
+}
+
+// AsAddrsOK is the same as AsAddrs, except it returns a boolean instead of panicking.
+func (a A) AsAddrsOK() ([]netip.Addr, bool) {
+	s, err := parseArray(a, netip.ParseAddr)
+	return s, err == nil
+}
+
+// AsPrefixes returns the slice of netip.Prefix the array represents. It panics if one
+// of elements is not a string holding an IP prefix in CIDR notation netip.ParsePrefix
+// accepts.
+func (a A) AsPrefixes() []netip.Prefix {
+	return must(parseArray(a, netip.ParsePrefix))
+}
+
+// AsPrefixesOK is the same as AsPrefixes, except it returns a boolean instead of
+// panicking.
+func (a A) AsPrefixesOK() ([]netip.Prefix, bool) {
+	s, err := parseArray(a, netip.ParsePrefix)
+	return s, err == nil
+}
+
+// AsAddrPorts returns the slice of netip.AddrPort the array represents. It panics if
+// one of elements is not a string holding an IP address and port netip.ParseAddrPort
+// accepts.
+func (a A) AsAddrPorts() []netip.AddrPort {
+	return must(parseArray(a, netip.ParseAddrPort))
+}
+
+// AsAddrPortsOK is the same as AsAddrPorts, except it returns a boolean instead of
+// panicking.
+func (a A) AsAddrPortsOK() ([]netip.AddrPort, bool) {
+	s, err := parseArray(a, netip.ParseAddrPort)
+	return s, err == nil
+}
+
+// AsBytesSlice returns the slice of []byte the array represents. It panics if one of
+// elements is not a string holding base64 in any of the standard and URL encodings,
+// padded or not.
+func (a A) AsBytesSlice() [][]byte {
+	return must(parseArray(a, decodeBase64))
+}
+
+// AsBytesSliceOK is the same as AsBytesSlice, except it returns a boolean instead of
+// panicking.
+func (a A) AsBytesSliceOK() ([][]byte, bool) {
+	s, err := parseArray(a, decodeBase64)
+	return s, err == nil
+}
+
+// AsRegexps returns the slice of *regexp.Regexp the array represents. It panics if one
+// of elements is not a string holding a regular expression regexp.Compile accepts.
+func (a A) AsRegexps() []*regexp.Regexp {
+	return must(parseArray(a, regexp.Compile))
+}
+
+// AsRegexpsOK is the same as AsRegexps, except it returns a boolean instead of
+// panicking.
+func (a A) AsRegexpsOK() ([]*regexp.Regexp, bool) {
+	s, err := parseArray(a, regexp.Compile)
+	return s, err == nil
+}
+
+// AsMailAddresses returns the slice of *mail.Address the array represents. It panics if
+// one of elements is not a string holding an RFC 5322 address mail.ParseAddress
+// accepts.
+func (a A) AsMailAddresses() []*mail.Address {
+	return must(parseArray(a, mail.ParseAddress))
+}
+
+// AsMailAddressesOK is the same as AsMailAddresses, except it returns a boolean instead
+// of panicking.
+func (a A) AsMailAddressesOK() ([]*mail.Address, bool) {
+	s, err := parseArray(a, mail.ParseAddress)
+	return s, err == nil
+}
+
+// must returns v, panicking if err is not nil.
+func must[T any](v T, err error) T {
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// parseValue parses the string value for given key within a with parse.
+func parseValue[T any](a any, key string, parse func(string) (T, error)) (T, error) {
+	s, err := lookupErr[string](a, key)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return parse(s)
+}
+
+// parseArray parses the strings of a with parse.
+func parseArray[T any](a A, parse func(string) (T, error)) ([]T, error) {
+	s := make([]T, len(a))
+	for i, v := range a {
+		str, err := convert[string](v)
+		if err != nil {
+			return nil, fmt.Errorf("element %d: %w", i, err)
+		}
+		if s[i], err = parse(str); err != nil {
+			return nil, fmt.Errorf("element %d: %w", i, err)
+		}
+	}
+	return s, nil
+}
+
+func asTextErr(a any, key string, v encoding.TextUnmarshaler) error {
+	s, err := lookupErr[string](a, key)
+	if err != nil {
+		return err
+	}
+	return v.UnmarshalText([]byte(s))
+}
+
+var base64Encodings = []*base64.Encoding{
+	base64.StdEncoding,
+	base64.URLEncoding,
+	base64.RawStdEncoding,
+	base64.RawURLEncoding,
+}
+
+// decodeBase64 decodes s in the first of the standard and URL encodings, padded or
+// not, it is valid in.
+func decodeBase64(s string) ([]byte, error) {
+	var err error
+	for _, enc := range base64Encodings {
+		var b []byte
+		if b, err = enc.DecodeString(s); err == nil {
+			return b, nil
+		}
+	}
+	return nil, err
+}
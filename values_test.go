@@ -0,0 +1,87 @@
+package typed
+
+import (
+	"net/netip"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestValues(t *testing.T) {
+	t.Parallel()
+
+	m := M{
+		"endpoint": "https://api.example.com:8443/v1?debug=1",
+		"dns":      "2001:db8::1",
+		"subnet":   "10.0.0.0/8",
+		"listen":   "127.0.0.1:8080",
+		"key":      "aGVsbG8/Pw==",
+		"token":    "aGVsbG8_Pw",
+		"pattern":  `^order\.(\w+)$`,
+		"owner":    "Ops Team <ops@example.com>",
+		"since":    "2024-03-01T12:30:00Z",
+		"bad":      "%zz",
+		"group":    "(",
+		"count":    1.0,
+	}
+
+	u := m.AsURL("endpoint")
+	equal(t, "api.example.com:8443", u.Host)
+	equal(t, "1", u.Query().Get("debug"))
+	equal(t, netip.MustParseAddr("2001:db8::1"), m.AsAddr("dns"))
+	equal(t, true, m.AsPrefix("subnet").Contains(netip.MustParseAddr("10.1.2.3")))
+	equal(t, uint16(8080), m.AsAddrPort("listen").Port())
+	equal(t, "hello??", string(m.AsBytes("key")))
+	equal(t, "hello??", string(D{{"token", m["token"]}}.AsBytes("token")))
+	equal(t, "paid", m.AsRegexp("pattern").FindStringSubmatch("order.paid")[1])
+	equal(t, "ops@example.com", m.AsMailAddress("owner").Address)
+	equal(t, "Ops Team", m.AsMailAddress("owner").Name)
+
+	var since time.Time
+	m.AsText("since", &since)
+	equal(t, 2024, since.Year())
+	var addr netip.Addr
+	equal(t, true, A{"::1"}.AsTextOK("0", &addr))
+	equal(t, true, addr.IsLoopback())
+
+	for _, ok := range []bool{
+		func() bool { _, ok := m.AsURLOK("bad"); return ok }(),
+		func() bool { _, ok := m.AsAddrOK("subnet"); return ok }(),
+		func() bool { _, ok := m.AsPrefixOK("dns"); return ok }(),
+		func() bool { _, ok := m.AsAddrPortOK("dns"); return ok }(),
+		func() bool { _, ok := m.AsBytesOK("bad"); return ok }(),
+		func() bool { _, ok := m.AsRegexpOK("group"); return ok }(),
+		func() bool { _, ok := m.AsRegexpOK("count"); return ok }(),
+		func() bool { _, ok := m.AsMailAddressOK("dns"); return ok }(),
+		m.AsTextOK("count", &since),
+		m.AsTextOK("dns", &since),
+	} {
+		equal(t, false, ok)
+	}
+	equal(t, true, panics(func() { m.AsAddr("missing") }))
+	equal(t, true, panics(func() { m.AsText("bad", &addr) }))
+}
+
+func TestA_Values(t *testing.T) {
+	t.Parallel()
+
+	a := A{"10.0.0.1", "::1"}
+	equalSlice(t, []netip.Addr{netip.MustParseAddr("10.0.0.1"), netip.MustParseAddr("::1")}, a.AsAddrs())
+	_, ok := a.AsPrefixesOK()
+	equal(t, false, ok)
+	_, ok = A{"10.0.0.1", 1.0}.AsAddrsOK()
+	equal(t, false, ok)
+
+	equal(t, "/b", A{"https://a/b"}.AsURLs()[0].Path)
+	equal(t, 2, len(A{"10.0.0.0/8", "fd00::/8"}.AsPrefixes()))
+	equal(t, uint16(53), A{"[::1]:53"}.AsAddrPorts()[0].Port())
+	equal(t, "hi", string(A{"aGk="}.AsBytesSlice()[0]))
+	equal(t, true, A{"a+", "b"}.AsRegexps()[0].MatchString("aaa"))
+	equal(t, "a@b.c", A{"a@b.c"}.AsMailAddresses()[0].Address)
+	equal(t, true, panics(func() { A{"("}.AsRegexps() }))
+
+	_, err := parseArray(A{"a", "("}, regexp.Compile)
+	equal(t, "element 1: error parsing regexp: missing closing ): `(`", err.Error())
+	_, err = parseArray(A{"10.0.0.1", 1.0}, netip.ParseAddr)
+	equal(t, "element 1: interface conversion: interface {} is float64, not string", err.Error())
+}
@@ -0,0 +1,133 @@
+package typed
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+)
+
+// A Kind is the JSON type of a value.
+type Kind int
+
+const (
+	KindInvalid Kind = iota // not a JSON value
+	KindNull
+	KindBool
+	KindNumber
+	KindString
+	KindDocument
+	KindArray
+)
+
+var kindNames = [...]string{
+	KindInvalid:  "invalid",
+	KindNull:     "null",
+	KindBool:     "bool",
+	KindNumber:   "number",
+	KindString:   "string",
+	KindDocument: "document",
+	KindArray:    "array",
+}
+
+func (k Kind) String() string {
+	if k < 0 || int(k) >= len(kindNames) {
+		return "Kind(" + strconv.Itoa(int(k)) + ")"
+	}
+	return kindNames[k]
+}
+
+// KindOf returns the JSON type of v, which holds M, D, A or the values of
+// encoding/json.
+func KindOf(v any) Kind {
+	switch v.(type) {
+	case nil:
+		return KindNull
+	case bool:
+		return KindBool
+	case float64, json.Number:
+		return KindNumber
+	case string:
+		return KindString
+	case M, D, map[string]any:
+		return KindDocument
+	case A, []any:
+		return KindArray
+	}
+	return KindInvalid
+}
+
+// SkipChildren is used as a return value from a WalkFunc to indicate that the
+// children of the document or array of the call are to be skipped. It is not
+// returned as an error by Walk.
+var SkipChildren = errors.New("skip children")
+
+// WalkFunc is the type of the function called by Walk to visit each value. The path
+// is the path of the value within the document walked, in the syntax of the
+// accessors, with its keys escaped; it is empty for the document itself.
+//
+// If the function returns SkipChildren when invoked on a document or array, Walk
+// skips its children. If it returns any other non-nil error, Walk stops and returns
+// that error.
+type WalkFunc func(path Path, kind Kind, value any) error
+
+// Walk walks the values of doc depth-first, calling fn for doc itself and each value
+// within it. The keys of an M are visited in sorted order, and those of a D in document
+// order.
+func Walk(doc any, fn WalkFunc) error {
+	err := walkValue("", doc, fn)
+	if err == SkipChildren {
+		return nil
+	}
+	return err
+}
+
+func walkValue(path Path, v any, fn WalkFunc) error {
+	kind := KindOf(v)
+	if err := fn(path, kind, v); err != nil || kind != KindDocument && kind != KindArray {
+		return err
+	}
+
+	switch x := v.(type) {
+	case M:
+		for k, v := range x.All() {
+			if err := walkChild(path, EscapeKey(k), v, fn); err != nil {
+				return err
+			}
+		}
+	case map[string]any:
+		for k, v := range M(x).All() {
+			if err := walkChild(path, EscapeKey(k), v, fn); err != nil {
+				return err
+			}
+		}
+	case D:
+		for k, v := range x.All() {
+			if err := walkChild(path, EscapeKey(k), v, fn); err != nil {
+				return err
+			}
+		}
+	case A:
+		for i, v := range x {
+			if err := walkChild(path, strconv.Itoa(i), v, fn); err != nil {
+				return err
+			}
+		}
+	case []any:
+		for i, v := range x {
+			if err := walkChild(path, strconv.Itoa(i), v, fn); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func walkChild(path Path, key string, v any, fn WalkFunc) error {
+	if path != "" {
+		key = path + "." + key
+	}
+	if err := walkValue(key, v, fn); err != SkipChildren {
+		return err
+	}
+	return nil
+}
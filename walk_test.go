@@ -0,0 +1,79 @@
+package typed
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestWalk(t *testing.T) {
+	t.Parallel()
+
+	doc := M{
+		"name":   "Wednesday",
+		"age":    6.0,
+		"pet":    nil,
+		"labels": M{"app.kubernetes.io/name": "web"},
+		"items":  A{M{"ok": true}, "x"},
+		"skip":   M{"hidden": 1.0},
+	}
+
+	var visits []string
+	err := Walk(doc, func(path Path, kind Kind, value any) error {
+		visits = append(visits, fmt.Sprintf("%s:%s", path, kind))
+		if path != "" {
+			// Every path is addressable by the accessors.
+			v, _, result := walk(doc, path)
+			equal(t, lookupFound, result)
+			equal(t, kind, KindOf(v))
+		}
+		if path == "skip" {
+			return SkipChildren
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	equalSlice(t, []string{
+		":document",
+		"age:number",
+		"items:array",
+		"items.0:document",
+		"items.0.ok:bool",
+		"items.1:string",
+		"labels:document",
+		`labels.app\.kubernetes\.io/name:string`,
+		"name:string",
+		"pet:null",
+		"skip:document",
+	}, visits)
+}
+
+func TestWalk_Stop(t *testing.T) {
+	t.Parallel()
+
+	errStop := errors.New("stop")
+	var n int
+	err := Walk(A{1.0, A{2.0, 3.0}, 4.0}, func(path Path, kind Kind, value any) error {
+		n++
+		if path == "1.0" {
+			return errStop
+		}
+		return nil
+	})
+	equal(t, errStop, err)
+	equal(t, 4, n)
+
+	err = Walk(D{{"a", 1.0}}, func(Path, Kind, any) error { return SkipChildren })
+	equal(t, nil, err)
+}
+
+func TestKind(t *testing.T) {
+	t.Parallel()
+
+	equal(t, "number", KindOf(1.0).String())
+	equal(t, KindInvalid, KindOf(1))
+	equal(t, "Kind(42)", Kind(42).String())
+}